@@ -0,0 +1,29 @@
+// Recalcula a reputação de todos os usuários a partir das curtidas, votos, respostas
+// aceitas, seguidores e moderação existentes, aplicando as regras atuais
+// (REPUTATION_RULES). Uso: go run ./cmd/reputation
+package main
+
+import (
+	"api/src/config"
+	"api/src/database"
+	"api/src/repository"
+	"fmt"
+	"log"
+)
+
+func main() {
+	config.LoadEnv()
+
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco: %v", err)
+	}
+	defer db.Close()
+
+	events, err := repository.NewReputationRepository(db).Recompute()
+	if err != nil {
+		log.Fatalf("Erro ao recalcular reputação: %v", err)
+	}
+
+	fmt.Printf("Reputação recalculada: %d eventos registrados\n", events)
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"api/src/config"
+	"api/src/media"
 	"api/src/middleware"
 	"api/src/router"
+	"api/src/scheduler"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +14,9 @@ import (
 func main() {
 	config.LoadEnv()
 
+	scheduler.Start()
+	media.Start(config.ImageWorkers, config.ImageQueueSize)
+
 	r := router.Generate()
 
 	handler := middleware.EnableCORS(r)
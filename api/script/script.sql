@@ -6,44 +6,215 @@ CREATE TABLE if not exists users (
     email VARCHAR(100) NOT NULL UNIQUE,
     nick VARCHAR(50) NOT NULL UNIQUE,
     password VARCHAR(255) NOT NULL,
+    avatar_key VARCHAR(255) NULL,
+    banner_key VARCHAR(255) NULL,
+    -- Tamanhos gerados no envio de cada imagem, do menor para o maior ("small,medium,large")
+    avatar_variants VARCHAR(255) NULL,
+    banner_variants VARCHAR(255) NULL,
+    bio TEXT NULL,
+    website VARCHAR(255) NULL,
+    location VARCHAR(100) NULL,
+    pronouns VARCHAR(50) NULL,
+    github VARCHAR(39) NULL,
+    gitlab VARCHAR(255) NULL,
+    -- Contadores do perfil, mantidos pela API (ver repository/stats.go)
+    followers_count INT NOT NULL DEFAULT 0,
+    following_count INT NOT NULL DEFAULT 0,
+    posts_count INT NOT NULL DEFAULT 0,
+    likes_received_count INT NOT NULL DEFAULT 0,
+    is_private BOOLEAN NOT NULL DEFAULT FALSE,
+    role ENUM('user', 'moderator', 'admin') NOT NULL DEFAULT 'user',
+    -- Soma dos pontos em reputation_events (ver repository/reputation.go)
+    reputation INT NOT NULL DEFAULT 0,
     createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 )Engine=InnoDB DEFAULT CHARSET=utf8mb4;
 
-CREATE TABLE followers (
-    author_id BIGINT NOT NULL,
-    follows_id BIGINT NOT NULL,
+CREATE TABLE IF NOT EXISTS user_tech_stack (
+    user_id BIGINT UNSIGNED NOT NULL,
+    tag VARCHAR(30) NOT NULL,
+    position INT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, tag),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+)Engine=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS followers (
+    follower_id BIGINT UNSIGNED NOT NULL,
+    following_id BIGINT UNSIGNED NOT NULL,
+    -- Quando o usuário passou a seguir (ou teve o pedido aprovado)
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 
-    FOREIGN KEY (user_id)
+    FOREIGN KEY (follower_id)
         REFERENCES users(id) ON DELETE CASCADE,
 
-    FOREIGN KEY (follows_id)
+    FOREIGN KEY (following_id)
         REFERENCES users(id) ON DELETE CASCADE,
 
-    PRIMARY KEY (user_id, follows_id)
+    PRIMARY KEY (follower_id, following_id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
+-- Pedidos pendentes para seguir contas privadas
+CREATE TABLE IF NOT EXISTS follow_requests (
+    requester_id BIGINT UNSIGNED NOT NULL,
+    target_id BIGINT UNSIGNED NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (requester_id, target_id),
+    INDEX idx_follow_requests_target (target_id, createdAt),
+    FOREIGN KEY (requester_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (target_id) REFERENCES users(id) ON DELETE CASCADE
+)Engine=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Bloqueios: escondem um usuário do outro nos dois sentidos
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id BIGINT UNSIGNED NOT NULL,
+    blocked_id BIGINT UNSIGNED NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id),
+    INDEX idx_blocks_blocked (blocked_id),
+    FOREIGN KEY (blocker_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (blocked_id) REFERENCES users(id) ON DELETE CASCADE
+)Engine=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Contas silenciadas: somem dos feeds de quem silenciou, até expires_at (NULL = sem prazo)
+CREATE TABLE IF NOT EXISTS muted_users (
+    user_id BIGINT UNSIGNED NOT NULL,
+    muted_id BIGINT UNSIGNED NOT NULL,
+    expires_at TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, muted_id),
+    INDEX idx_muted_users_expires (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (muted_id) REFERENCES users(id) ON DELETE CASCADE
+)Engine=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Palavras, frases e hashtags silenciadas. "pattern" é a expressão regular
+-- montada pela API a partir da frase e das opções (model.MutedKeyword.Pattern)
+CREATE TABLE IF NOT EXISTS muted_keywords (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    phrase VARCHAR(100) NOT NULL,
+    pattern VARCHAR(255) NOT NULL,
+    whole_word BOOLEAN NOT NULL DEFAULT TRUE,
+    case_sensitive BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_muted_keywords_user (user_id),
+    INDEX idx_muted_keywords_expires (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+)Engine=InnoDB DEFAULT CHARSET=utf8mb4;
+
 -- Tabela de posts
 CREATE TABLE IF NOT EXISTS posts (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-    title VARCHAR(100) NOT NULL,
+    title VARCHAR(100) NOT NULL DEFAULT '',
     content TEXT NOT NULL,
     author_id BIGINT UNSIGNED NOT NULL,
+    visibility ENUM('public', 'followers', 'unlisted', 'private') NOT NULL DEFAULT 'public',
+    post_type ENUM('post', 'question') NOT NULL DEFAULT 'post',
+    status ENUM('draft', 'scheduled', 'published') NOT NULL DEFAULT 'published',
+    publish_at TIMESTAMP NULL DEFAULT NULL,
+    edited_at TIMESTAMP NULL DEFAULT NULL,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    -- Moderação dos comentários pelo autor: bloqueio e quem pode comentar
+    comments_locked BOOLEAN NOT NULL DEFAULT FALSE,
+    comment_policy ENUM('everyone', 'followers') NOT NULL DEFAULT 'everyone',
+    -- Citação: post embutido neste (ver repository/reposts.go)
+    quoted_post_id BIGINT UNSIGNED NULL DEFAULT NULL,
     createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 
+    INDEX idx_posts_author_status (author_id, status),
+    INDEX idx_posts_status_publish_at (status, publish_at),
+    INDEX idx_posts_deleted_at (deleted_at),
+    INDEX idx_posts_type (post_type, createdAt),
+    INDEX idx_posts_quoted (quoted_post_id, createdAt),
+
     FOREIGN KEY (author_id)
-        REFERENCES users(id) ON DELETE CASCADE
-        createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+        REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (quoted_post_id)
+        REFERENCES posts(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Histórico de revisões dos posts
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    post_id BIGINT UNSIGNED NOT NULL,
+    editor_id BIGINT UNSIGNED NOT NULL,
+    title VARCHAR(100) NOT NULL,
+    content TEXT NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_post_revisions_post (post_id, id),
+
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Tabela de likes: cada linha é a reação de um usuário a um post
+
+-- Reposts: o post aparece de novo, com a atribuição, no feed de quem segue o usuário
+CREATE TABLE IF NOT EXISTS reposts (
+    user_id BIGINT UNSIGNED NOT NULL,
+    post_id BIGINT UNSIGNED NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, post_id),
+    INDEX idx_reposts_post (post_id, createdAt),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
--- Tabela de likes
+-- Posts salvos. Os favoritos são sempre privados; as coleções são listas nomeadas,
+-- públicas ou privadas. Em ambos, position define a ordem escolhida pelo usuário
+-- (novos itens entram no topo). Posts excluídos ou que deixaram de ser visíveis
+-- aparecem como indisponíveis; ao serem apagados de fato, saem em cascata.
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id BIGINT UNSIGNED NOT NULL,
+    post_id BIGINT UNSIGNED NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, post_id),
+    INDEX idx_bookmarks_order (user_id, position),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS collections (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    is_public BOOLEAN NOT NULL DEFAULT FALSE,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_collections_user (user_id, createdAt),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS collection_posts (
+    collection_id BIGINT UNSIGNED NOT NULL,
+    post_id BIGINT UNSIGNED NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (collection_id, post_id),
+    INDEX idx_collection_posts_order (collection_id, position),
+
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 CREATE TABLE IF NOT EXISTS likes (
     user_id BIGINT UNSIGNED NOT NULL,
     post_id BIGINT UNSIGNED NOT NULL,
+    -- Reação escolhida (uma por usuário e post); o like é a reação 👍
+    reaction VARCHAR(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL DEFAULT '👍',
 
     createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 
     PRIMARY KEY (user_id, post_id),
+    INDEX idx_likes_reaction (post_id, reaction, createdAt),
 
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
     FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE
@@ -53,10 +224,156 @@ CREATE TABLE IF NOT EXISTS likes (
 CREATE TABLE IF NOT EXISTS comments (
     id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
     post_id BIGINT UNSIGNED NOT NULL,
-    author_id BIGINT UNSIGNED NOT NULL,
+    -- Comentário respondido (NULL no primeiro nível) e a profundidade na conversa
+    parent_id BIGINT UNSIGNED NULL,
+    depth TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    -- NULL só em marcadores "[removed]" de usuários excluídos (ver UserRepository.Delete)
+    author_id BIGINT UNSIGNED NULL,
     content TEXT NOT NULL,
+    edited_at TIMESTAMP NULL DEFAULT NULL,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    -- Quem excluiu: o próprio autor ou o autor do post (que o autor do comentário não pode restaurar)
+    deleted_by BIGINT UNSIGNED NULL DEFAULT NULL,
+    -- Moderação do autor do post: comentário oculto e comentário fixado (no máximo um por post)
+    hidden_at TIMESTAMP NULL DEFAULT NULL,
+    pinned_at TIMESTAMP NULL DEFAULT NULL,
+    -- Preenchida só no comentário fixado: o índice único garante um por post
+    pinned_post_id BIGINT UNSIGNED AS (IF(pinned_at IS NULL, NULL, post_id)) STORED,
+    -- Perguntas: saldo dos votos da resposta e a resposta aceita (no máximo uma por post)
+    score INT NOT NULL DEFAULT 0,
+    accepted_at TIMESTAMP NULL DEFAULT NULL,
     createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 
+    INDEX idx_comments_deleted_at (deleted_at),
+    INDEX idx_comments_thread (post_id, parent_id, createdAt),
+    INDEX idx_comments_parent (parent_id, createdAt),
+    INDEX idx_comments_accepted (post_id, accepted_at),
+    UNIQUE INDEX idx_comments_pinned (pinned_post_id),
+
     FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
-    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
-) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
\ No newline at end of file
+    -- Só comentários sem respostas são apagados de fato (ver TrashRepository.PurgeComments)
+    FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE SET NULL,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Histórico de edições dos comentários, visível para moderadores
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    comment_id BIGINT UNSIGNED NOT NULL,
+    editor_id BIGINT UNSIGNED NOT NULL,
+    content TEXT NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_comment_revisions_comment (comment_id),
+
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (editor_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Reações dos usuários aos comentários; a collation binária diferencia os emojis
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    emoji VARCHAR(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (comment_id, user_id, emoji),
+    INDEX idx_comment_reactions_emoji (comment_id, emoji, createdAt),
+
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Votos nas respostas das perguntas: +1 ou -1 por usuário
+CREATE TABLE IF NOT EXISTS comment_votes (
+    comment_id BIGINT UNSIGNED NOT NULL,
+    user_id BIGINT UNSIGNED NOT NULL,
+    value TINYINT NOT NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (comment_id, user_id),
+
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Histórico de reputação: cada linha é um evento que deu (ou tirou) pontos de user_id.
+-- source_key identifica a origem (ex.: "post:usuário" de uma curtida) e impede que o
+-- mesmo evento conte duas vezes. Os pontos ficam gravados para que mudanças nas regras
+-- só valham para eventos novos (ou depois de rodar cmd/reputation).
+CREATE TABLE IF NOT EXISTS reputation_events (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    event VARCHAR(32) NOT NULL,
+    points INT NOT NULL,
+    source_key VARCHAR(64) NOT NULL,
+    post_id BIGINT UNSIGNED NULL,
+    comment_id BIGINT UNSIGNED NULL,
+    actor_id BIGINT UNSIGNED NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_reputation_source (event, source_key),
+    INDEX idx_reputation_user (user_id, createdAt),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Conquistas dos usuários (catálogo em model.Badges). As automáticas são concedidas
+-- pelo agendador e não são retiradas; as manuais têm granted_by, o administrador.
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_id BIGINT UNSIGNED NOT NULL,
+    badge VARCHAR(32) NOT NULL,
+    granted_by BIGINT UNSIGNED NULL,
+    awardedAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (user_id, badge),
+    INDEX idx_user_badges_badge (badge, awardedAt),
+
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (granted_by) REFERENCES users(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Anexos de posts e comentários. Ao remover o post ou comentário o anexo fica órfão
+-- (post_id e comment_id nulos) e o agendador apaga o arquivo do armazenamento.
+CREATE TABLE IF NOT EXISTS attachments (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    owner_id BIGINT UNSIGNED NULL,
+    post_id BIGINT UNSIGNED NULL,
+    comment_id BIGINT UNSIGNED NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+    status ENUM('processing', 'ready', 'failed') NOT NULL DEFAULT 'ready',
+    alt_text VARCHAR(500) NULL,
+    width INT UNSIGNED NULL,
+    height INT UNSIGNED NULL,
+    -- Falhas ao apagar os arquivos do armazenamento adiam a próxima tentativa
+    purge_attempts INT UNSIGNED NOT NULL DEFAULT 0,
+    purge_retry_at TIMESTAMP NULL,
+    createdAt TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_attachments_owner (owner_id),
+    INDEX idx_attachments_status (status, createdAt),
+
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE SET NULL,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- Tamanhos gerados para cada imagem anexada (miniatura, feed, tamanho cheio...)
+CREATE TABLE IF NOT EXISTS attachment_variants (
+    attachment_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(30) NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    width INT UNSIGNED NOT NULL,
+    height INT UNSIGNED NOT NULL,
+    size BIGINT UNSIGNED NOT NULL,
+
+    PRIMARY KEY (attachment_id, name),
+
+    FOREIGN KEY (attachment_id) REFERENCES attachments(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
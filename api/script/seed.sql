@@ -8,7 +8,7 @@ VALUES
 ('Pedro Alves', 'pedro@example.com', 'pedroa', '$2a$10$95IZKinqGPVbbZZuKB88ee/Yct1AE/vGqEM2NIjIbSjqKizNdnG06 ');
 
 -- Seguidores
-INSERT INTO followers (user_id, follows_id)
+INSERT INTO followers (follower_id, following_id)
 VALUES
 (1, 2),   -- João segue Mariana
 (1, 3),   -- João segue Carlos
@@ -16,3 +16,18 @@ VALUES
 (3, 1),   -- Carlos segue João
 (4, 5),   -- Ana segue Pedro
 (5, 1);   -- Pedro segue João
+
+-- Contadores do perfil: os inserts acima não passam pela API, então são recalculados aqui.
+-- O mesmo UPDATE serve para corrigir os contadores de uma base já existente.
+UPDATE users u
+SET u.followers_count = (SELECT COUNT(*) FROM followers WHERE following_id = u.id),
+    u.following_count = (SELECT COUNT(*) FROM followers WHERE follower_id = u.id),
+    u.posts_count = (
+        SELECT COUNT(*) FROM posts
+        WHERE author_id = u.id AND status = 'published' AND deleted_at IS NULL
+    ),
+    u.likes_received_count = (
+        SELECT COUNT(*) FROM likes l
+        JOIN posts p ON p.id = l.post_id
+        WHERE p.author_id = u.id AND p.status = 'published' AND p.deleted_at IS NULL
+    );
@@ -0,0 +1,168 @@
+// Imagens do perfil: endereços públicos da foto e da capa, e o avatar padrão
+// gerado a partir do ID do usuário
+package avatar
+
+import (
+	"api/src/config"
+	"api/src/storage"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+// Tamanho exibido por padrão nas respostas da API, quando configurado
+const DisplayVariant = "medium"
+
+// URL retorna o endereço da foto de perfil do usuário no tamanho padrão, ou do
+// identicon gerado pela API quando ele ainda não enviou uma foto. variants é a lista
+// de tamanhos gravada junto com a foto (ver Names).
+func URL(userID uint64, key, variants *string) string {
+	if key == nil || *key == "" {
+		return IdenticonURL(userID)
+	}
+	names := avatarNames(variants)
+	return storage.PublicURL(variantKey(names, *key, displayVariant(names)))
+}
+
+// displayVariant usa o tamanho "medium" ou, se a foto não o tiver, o maior
+func displayVariant(names []string) string {
+	for _, name := range names {
+		if name == DisplayVariant {
+			return name
+		}
+	}
+	if len(names) == 0 {
+		return DisplayVariant
+	}
+	return names[len(names)-1]
+}
+
+// IdenticonURL é o endereço do avatar padrão do usuário
+func IdenticonURL(userID uint64) string {
+	return fmt.Sprintf("%s/users/%d/avatar/identicon.png", config.APIPublicURL, userID)
+}
+
+// Names é a lista de tamanhos gerados, do menor para o maior, no formato gravado
+// no banco junto com a chave da imagem. As chaves e endereços de uma imagem já
+// enviada saem dessa lista, e não da configuração atual, para que mudar
+// AVATAR_VARIANTS ou BANNER_VARIANTS não quebre os endereços nem deixe arquivos
+// esquecidos no armazenamento.
+func Names(variants []config.ImageVariant) string {
+	return strings.Join(configNames(variants), ",")
+}
+
+// VariantKey retorna a chave de um dos tamanhos de uma foto sendo enviada. O maior
+// tamanho é gravado na própria chave principal, assim como nos anexos.
+func VariantKey(key, name string) string {
+	return variantKey(configNames(config.AvatarVariants), key, name)
+}
+
+// Keys lista as chaves de todos os tamanhos gravados de uma foto
+func Keys(key string, variants *string) []string {
+	return variantKeys(avatarNames(variants), key)
+}
+
+// Sizes retorna o endereço de cada tamanho gravado de uma foto
+func Sizes(key string, variants *string) map[string]string {
+	names := avatarNames(variants)
+
+	sizes := make(map[string]string, len(names))
+	for _, name := range names {
+		sizes[name] = storage.PublicURL(variantKey(names, key, name))
+	}
+	return sizes
+}
+
+// BannerURL retorna o endereço da imagem de capa no maior tamanho; sem capa, nil
+func BannerURL(key *string) *string {
+	if key == nil || *key == "" {
+		return nil
+	}
+	url := storage.PublicURL(*key)
+	return &url
+}
+
+// BannerVariantKey é o equivalente de VariantKey para a imagem de capa
+func BannerVariantKey(key, name string) string {
+	return variantKey(configNames(config.BannerVariants), key, name)
+}
+
+// BannerKeys lista as chaves de todos os tamanhos gravados de uma capa
+func BannerKeys(key string, variants *string) []string {
+	return variantKeys(storedNames(variants, config.BannerVariants), key)
+}
+
+// avatarNames lê os tamanhos gravados de uma foto
+func avatarNames(variants *string) []string {
+	return storedNames(variants, config.AvatarVariants)
+}
+
+// storedNames lê a lista gravada por Names. Imagens enviadas antes de a lista ser
+// gravada usam a configuração atual, com que foram geradas.
+func storedNames(variants *string, fallback []config.ImageVariant) []string {
+	if variants == nil || *variants == "" {
+		return configNames(fallback)
+	}
+	return strings.Split(*variants, ",")
+}
+
+func configNames(variants []config.ImageVariant) []string {
+	names := make([]string, len(variants))
+	for i, variant := range variants {
+		names[i] = variant.Name
+	}
+	return names
+}
+
+func variantKey(names []string, key, name string) string {
+	if len(names) > 0 && names[len(names)-1] == name {
+		return key
+	}
+	return storage.VariantKey(key, name)
+}
+
+func variantKeys(names []string, key string) []string {
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		keys = append(keys, variantKey(names, key, name))
+	}
+	return keys
+}
+
+// Identicon desenha um padrão 5x5 simétrico, sempre o mesmo para o mesmo usuário
+func Identicon(userID uint64, size int) image.Image {
+	var id [8]byte
+	binary.BigEndian.PutUint64(id[:], userID)
+	sum := sha256.Sum256(id[:])
+
+	background := color.RGBA{240, 240, 240, 255}
+	foreground := color.RGBA{sum[0]/2 + 64, sum[1]/2 + 64, sum[2]/2 + 64, 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	const cells = 5
+	margin := size / 10
+	cell := (size - 2*margin) / cells
+	offset := (size - cell*cells) / 2
+
+	// Só as três primeiras colunas vêm do hash; as duas últimas espelham as primeiras
+	for row := 0; row < cells; row++ {
+		for col := 0; col < 3; col++ {
+			if sum[3+row*3+col]%2 == 0 {
+				continue
+			}
+
+			for _, x := range []int{col, cells - 1 - col} {
+				rect := image.Rect(offset+x*cell, offset+row*cell, offset+(x+1)*cell, offset+(row+1)*cell)
+				draw.Draw(img, rect, &image.Uniform{foreground}, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	return img
+}
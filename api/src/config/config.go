@@ -2,8 +2,13 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,8 +19,71 @@ var (
 	DBName     string
 	APIPort    string
 	JWTSecret  string
+
+	// Intervalo entre as execuções das tarefas em segundo plano
+	SchedulerInterval time.Duration
+
+	// Intervalo mínimo entre as verificações de conquistas automáticas
+	BadgeInterval time.Duration
+
+	// Tempo que posts e comentários excluídos ficam na lixeira
+	TrashRetention time.Duration
+
+	// Estilos do chroma usados no destaque de sintaxe dos temas claro e escuro
+	HighlightLightStyle string
+	HighlightDarkStyle  string
+
+	// Armazenamento de anexos: "local" (disco) ou "s3" (serviço compatível com S3)
+	StorageDriver    string
+	StorageLocalDir  string
+	StoragePublicURL string
+	S3Endpoint       string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3Bucket         string
+	S3Region         string
+	S3UseSSL         bool
+
+	// Limites de upload: tamanho por arquivo e cota total por usuário, em bytes
+	MaxUploadBytes int64
+	UserQuotaBytes int64
+
+	// Processamento de imagens: tamanhos gerados, qualidade JPEG e fila de trabalho
+	ImageVariants    []ImageVariant
+	ImageJPEGQuality int
+	ImageWorkers     int
+	ImageQueueSize   int
+
+	// Tamanhos gerados para as fotos de perfil e para as imagens de capa
+	AvatarVariants []ImageVariant
+	BannerVariants []ImageVariant
+
+	// Profundidade máxima das respostas a comentários
+	CommentMaxDepth int
+
+	// Prazo após a criação em que o autor ainda pode editar um comentário
+	CommentEditWindow time.Duration
+
+	// Emojis aceitos como reação aos comentários e aos posts, na ordem em que são
+	// oferecidos. O like dos posts é sempre aceito, como a reação 👍.
+	CommentReactions []string
+	PostReactions    []string
+
+	// Endereço público da API, usado em links gerados por ela (ex.: avatares padrão)
+	APIPublicURL string
+
+	// Pontos de reputação por evento (ver model.Reputation*); eventos com 0 pontos
+	// não são registrados
+	ReputationRules map[string]int
 )
 
+// ImageVariant é um tamanho gerado para cada imagem enviada; MaxSize limita
+// o maior lado da imagem, em pixels
+type ImageVariant struct {
+	Name    string
+	MaxSize int
+}
+
 func LoadEnv() {
 	err := godotenv.Load()
 	if err != nil {
@@ -28,6 +96,46 @@ func LoadEnv() {
 	APIPort = os.Getenv("API_PORT")
 	JWTSecret = os.Getenv("JWT_SECRET")
 
+	SchedulerInterval = time.Duration(intEnv("SCHEDULER_INTERVAL_SECONDS", 30)) * time.Second
+	BadgeInterval = time.Duration(intEnv("BADGE_INTERVAL_MINUTES", 5)) * time.Minute
+	TrashRetention = time.Duration(intEnv("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour
+
+	HighlightLightStyle = stringEnv("HIGHLIGHT_LIGHT_STYLE", "github")
+	HighlightDarkStyle = stringEnv("HIGHLIGHT_DARK_STYLE", "github-dark")
+
+	StorageDriver = stringEnv("STORAGE_DRIVER", "local")
+	StorageLocalDir = stringEnv("STORAGE_LOCAL_DIR", "uploads")
+	StoragePublicURL = os.Getenv("STORAGE_PUBLIC_URL")
+	S3Endpoint = os.Getenv("S3_ENDPOINT")
+	S3AccessKey = os.Getenv("S3_ACCESS_KEY")
+	S3SecretKey = os.Getenv("S3_SECRET_KEY")
+	S3Bucket = os.Getenv("S3_BUCKET")
+	S3Region = os.Getenv("S3_REGION")
+	S3UseSSL = os.Getenv("S3_USE_SSL") == "true"
+
+	MaxUploadBytes = int64(intEnv("MAX_UPLOAD_MB", 10)) << 20
+	UserQuotaBytes = int64(intEnv("USER_QUOTA_MB", 200)) << 20
+
+	ImageVariants = imageVariantsEnv("IMAGE_VARIANTS", "thumb:320,feed:800,full:1920")
+	ImageJPEGQuality = intEnv("IMAGE_JPEG_QUALITY", 85)
+	ImageWorkers = intEnv("IMAGE_WORKERS", 2)
+	ImageQueueSize = intEnv("IMAGE_QUEUE_SIZE", 32)
+
+	AvatarVariants = imageVariantsEnv("AVATAR_VARIANTS", "small:48,medium:128,large:256")
+	BannerVariants = imageVariantsEnv("BANNER_VARIANTS", "medium:750,large:1500")
+
+	CommentMaxDepth = intEnv("COMMENT_MAX_DEPTH", 3)
+	CommentEditWindow = time.Duration(intEnv("COMMENT_EDIT_WINDOW_MINUTES", 15)) * time.Minute
+	CommentReactions = listEnv("COMMENT_REACTIONS", "👍,❤️,😂,😮,😢,🎉")
+	PostReactions = listEnv("POST_REACTIONS", "👍,❤️,🎉,🤔,🚀")
+
+	ReputationRules = reputationRulesEnv("REPUTATION_RULES", defaultReputationRules)
+
+	APIPublicURL = strings.TrimRight(stringEnv("API_PUBLIC_URL", "http://localhost:"+APIPort), "/")
+	if StoragePublicURL == "" && StorageDriver == "local" {
+		StoragePublicURL = APIPublicURL + "/uploads"
+	}
+
 	// Apenas confirma que as variáveis foram carregadas — sem mostrar senhas ou strings
 	if DBUser == "" || DBPassword == "" || DBName == "" {
 		log.Println("⚠️  Algumas variáveis de ambiente do banco de dados não foram definidas.")
@@ -44,3 +152,118 @@ func LoadEnv() {
 	}
 
 }
+
+// stringEnv lê uma variável de ambiente, usando o valor padrão quando ausente
+func stringEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// intEnv lê uma variável de ambiente numérica, usando o valor padrão quando ausente ou inválida
+func intEnv(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("⚠️  Valor inválido para %s, usando %d.\n", key, fallback)
+		return fallback
+	}
+
+	return parsed
+}
+
+// listEnv lê uma lista separada por vírgulas, sem itens vazios ou repetidos
+func listEnv(key, fallback string) []string {
+	items := parseList(stringEnv(key, fallback))
+	if len(items) == 0 {
+		log.Printf("⚠️  Valor inválido para %s, usando %s.\n", key, fallback)
+		items = parseList(fallback)
+	}
+	return items
+}
+
+func parseList(value string) []string {
+	var items []string
+	seen := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+// Regras de reputação padrão: cada evento e os pontos que dá (ou tira) de quem o recebe
+const defaultReputationRules = "post_liked:2,answer_upvoted:10,answer_downvoted:-2,answer_accepted:15," +
+	"follower_gained:1,comment_hidden:-5,comment_removed:-10"
+
+// reputationRulesEnv lê as regras no formato "evento:pontos,evento:pontos". Eventos
+// ausentes da variável mantêm os pontos padrão.
+func reputationRulesEnv(key, fallback string) map[string]int {
+	rules, _ := parseReputationRules(fallback)
+
+	custom, err := parseReputationRules(os.Getenv(key))
+	if err != nil {
+		log.Printf("⚠️  Valor inválido para %s, usando %s.\n", key, fallback)
+		return rules
+	}
+	for event, points := range custom {
+		rules[event] = points
+	}
+
+	return rules
+}
+
+func parseReputationRules(value string) (map[string]int, error) {
+	rules := map[string]int{}
+	if strings.TrimSpace(value) == "" {
+		return rules, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		event, points, found := strings.Cut(strings.TrimSpace(item), ":")
+		parsed, err := strconv.Atoi(strings.TrimSpace(points))
+		if !found || event == "" || err != nil {
+			return nil, fmt.Errorf("regra de reputação inválida: %q", item)
+		}
+		rules[event] = parsed
+	}
+	return rules, nil
+}
+
+// imageVariantsEnv lê a lista de variantes no formato "nome:tamanho,nome:tamanho",
+// ordenada do menor para o maior tamanho
+func imageVariantsEnv(key, fallback string) []ImageVariant {
+	variants, err := parseImageVariants(stringEnv(key, fallback))
+	if err != nil {
+		log.Printf("⚠️  Valor inválido para %s, usando %s.\n", key, fallback)
+		variants, _ = parseImageVariants(fallback)
+	}
+
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].MaxSize < variants[j].MaxSize
+	})
+
+	return variants
+}
+
+func parseImageVariants(value string) ([]ImageVariant, error) {
+	var variants []ImageVariant
+	for _, item := range strings.Split(value, ",") {
+		name, size, found := strings.Cut(strings.TrimSpace(item), ":")
+		maxSize, err := strconv.Atoi(size)
+		if !found || name == "" || err != nil || maxSize <= 0 {
+			return nil, fmt.Errorf("variante de imagem inválida: %q", item)
+		}
+		variants = append(variants, ImageVariant{Name: name, MaxSize: maxSize})
+	}
+	return variants, nil
+}
@@ -0,0 +1,337 @@
+package controllers
+
+import (
+	"api/src/config"
+	"api/src/database"
+	"api/src/media"
+	"api/src/model"
+	"api/src/repository"
+	"api/src/storage"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Espaço extra permitido no corpo multipart além do próprio arquivo (cabeçalhos e campos)
+const multipartOverhead = 1 << 20
+
+// Anexa um arquivo a um post do usuário autenticado
+func UploadPostAttachment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	post, err := repository.NewPostsRepository(db).GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
+		return
+	}
+
+	if post.AuthorID != userID {
+		http.Error(w, "Sem permissão para anexar arquivos a este post", http.StatusForbidden)
+		return
+	}
+
+	uploadAttachment(w, r, db, model.Attachment{OwnerID: userID, PostID: &postID})
+}
+
+// Anexa um arquivo a um comentário do usuário autenticado
+func UploadCommentAttachment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	authorID, err := repository.NewCommentsRepository(db).GetAuthor(commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	if authorID != userID {
+		http.Error(w, "Sem permissão para anexar arquivos a este comentário", http.StatusForbidden)
+		return
+	}
+
+	uploadAttachment(w, r, db, model.Attachment{OwnerID: userID, CommentID: &commentID})
+}
+
+// Remove um anexo do usuário autenticado
+func DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	attachmentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewAttachmentsRepository(db)
+
+	attachment, err := repo.GetByID(attachmentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Anexo não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar anexo", http.StatusInternalServerError)
+		return
+	}
+
+	if attachment.OwnerID != userID {
+		http.Error(w, "Sem permissão para remover este anexo", http.StatusForbidden)
+		return
+	}
+
+	store, err := storage.New()
+	if err != nil {
+		http.Error(w, "Erro ao acessar armazenamento", http.StatusInternalServerError)
+		return
+	}
+
+	for _, key := range attachment.StorageKeys() {
+		if err := store.Delete(r.Context(), key); err != nil {
+			http.Error(w, "Erro ao remover arquivo", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := repo.Delete(attachmentID); err != nil {
+		http.Error(w, "Erro ao remover anexo", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Anexo removido",
+	})
+}
+
+// uploadAttachment lê o arquivo do campo "file", valida tipo, tamanho e cota,
+// grava no armazenamento e registra o anexo
+func uploadAttachment(w http.ResponseWriter, r *http.Request, db *sql.DB, attachment model.Attachment) {
+	r.Body = http.MaxBytesReader(w, r.Body, config.MaxUploadBytes+multipartOverhead)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Arquivo maior que o limite permitido", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Arquivo não enviado no campo \"file\"", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	if header.Size > config.MaxUploadBytes {
+		http.Error(w, "Arquivo maior que o limite permitido", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// O tipo é identificado pelo conteúdo; o Content-Type enviado pelo cliente é ignorado
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		http.Error(w, "Erro ao ler arquivo", http.StatusBadRequest)
+		return
+	}
+	contentType := http.DetectContentType(head[:n])
+
+	extension, allowed := model.AllowedAttachmentTypes[contentType]
+	if !allowed {
+		http.Error(w, "Tipo de arquivo não permitido: "+contentType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, "Erro ao ler arquivo", http.StatusInternalServerError)
+		return
+	}
+
+	// Imagens são processadas em segundo plano e exigem texto alternativo
+	outputType, imageExtension, isImage := media.OutputFormat(contentType)
+	if isImage {
+		alt := strings.TrimSpace(r.FormValue("alt"))
+		if alt == "" {
+			http.Error(w, "O texto alternativo (alt) é obrigatório para imagens", http.StatusBadRequest)
+			return
+		}
+		if len([]rune(alt)) > 500 {
+			http.Error(w, "O texto alternativo deve ter no máximo 500 caracteres", http.StatusBadRequest)
+			return
+		}
+		attachment.AltText = &alt
+		contentType = outputType
+		extension = imageExtension
+	}
+
+	repo := repository.NewAttachmentsRepository(db)
+
+	used, err := repo.UsedBytes(attachment.OwnerID)
+	if err != nil {
+		http.Error(w, "Erro ao verificar cota", http.StatusInternalServerError)
+		return
+	}
+	if used+header.Size > config.UserQuotaBytes {
+		http.Error(w, repository.ErrQuotaExceeded.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	key, err := storage.NewKey("attachments", extension)
+	if err != nil {
+		http.Error(w, "Erro ao gerar nome do arquivo", http.StatusInternalServerError)
+		return
+	}
+
+	attachment.StorageKey = key
+	attachment.Filename = attachmentFilename(header.Filename)
+	attachment.ContentType = contentType
+	attachment.Size = header.Size
+	attachment.Status = model.AttachmentReady
+
+	if isImage {
+		uploadImage(w, file, repo, attachment, outputType)
+		return
+	}
+
+	store, err := storage.New()
+	if err != nil {
+		http.Error(w, "Erro ao acessar armazenamento", http.StatusInternalServerError)
+		return
+	}
+
+	if err := store.Put(r.Context(), key, file, header.Size, contentType); err != nil {
+		http.Error(w, "Erro ao salvar arquivo", http.StatusInternalServerError)
+		return
+	}
+
+	attachmentID, err := repo.Create(attachment, config.UserQuotaBytes)
+	if err != nil {
+		// O registro falhou: o arquivo gravado não pode ficar sem dono
+		if deleteErr := store.Delete(r.Context(), key); deleteErr != nil {
+			log.Println("Erro ao remover arquivo não registrado:", deleteErr)
+		}
+
+		if errors.Is(err, repository.ErrQuotaExceeded) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Erro ao registrar anexo", http.StatusInternalServerError)
+		return
+	}
+
+	saved, err := repo.GetByID(attachmentID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar anexo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// uploadImage registra a imagem como "em processamento" e a envia para a fila.
+// O arquivo original nunca é gravado: apenas as versões recodificadas, sem metadados.
+func uploadImage(w http.ResponseWriter, file io.Reader, repo *repository.AttachmentsRepository, attachment model.Attachment, outputType string) {
+	raw, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Erro ao ler arquivo", http.StatusBadRequest)
+		return
+	}
+
+	attachment.Status = model.AttachmentProcessing
+
+	attachmentID, err := repo.Create(attachment, config.UserQuotaBytes)
+	if errors.Is(err, repository.ErrQuotaExceeded) {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao registrar anexo", http.StatusInternalServerError)
+		return
+	}
+
+	err = media.Submit(func() {
+		media.ProcessAttachment(attachmentID, attachment.StorageKey, outputType, raw)
+	})
+	if err != nil {
+		if deleteErr := repo.Delete(attachmentID); deleteErr != nil {
+			log.Println("Erro ao remover anexo não processado:", deleteErr)
+		}
+		http.Error(w, "Muitas imagens em processamento, tente novamente em instantes", http.StatusServiceUnavailable)
+		return
+	}
+
+	saved, err := repo.GetByID(attachmentID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar anexo", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// attachmentFilename mantém apenas o nome do arquivo enviado, sem diretórios
+func attachmentFilename(name string) string {
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		name = "arquivo"
+	}
+
+	runes := []rune(name)
+	if len(runes) > 255 {
+		name = string(runes[:255])
+	}
+	return name
+}
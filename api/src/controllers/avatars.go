@@ -0,0 +1,374 @@
+package controllers
+
+import (
+	"api/src/avatar"
+	"api/src/config"
+	"api/src/database"
+	"api/src/media"
+	"api/src/repository"
+	"api/src/storage"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"image/png"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Tamanho do identicon quando a requisição não informa ?size=
+const identiconDefaultSize = 128
+
+// Envia ou troca a foto de perfil do usuário autenticado. O recorte quadrado pode
+// ser informado pelos campos x, y e size; sem eles a imagem é recortada no centro.
+func UploadAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := avatarOwner(w, r)
+	if !ok {
+		return
+	}
+
+	raw, outputType, extension, ok := readProfileImage(w, r)
+	if !ok {
+		return
+	}
+
+	crop, err := avatarCrop(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	encoded, ok := processProfileImage(w, func() ([]media.Encoded, error) {
+		return media.Avatar(raw, outputType, crop)
+	})
+	if !ok {
+		return
+	}
+
+	variants := avatar.Names(config.AvatarVariants)
+	key, ok := storeProfileImage(w, r, "avatars", extension, outputType, encoded, avatar.VariantKey, func(repo *repository.UserRepository, key *string) (*string, *string, error) {
+		return repo.UpdateAvatar(userID, key, &variants)
+	}, avatar.Keys)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(avatarResponse(userID, &key, &variants))
+}
+
+// Envia ou troca a imagem de capa do perfil. A capa mantém a proporção original.
+func UploadBanner(w http.ResponseWriter, r *http.Request) {
+	userID, ok := avatarOwner(w, r)
+	if !ok {
+		return
+	}
+
+	raw, outputType, extension, ok := readProfileImage(w, r)
+	if !ok {
+		return
+	}
+
+	encoded, ok := processProfileImage(w, func() ([]media.Encoded, error) {
+		img, err := media.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		return media.Variants(img, outputType, config.BannerVariants)
+	})
+	if !ok {
+		return
+	}
+
+	variants := avatar.Names(config.BannerVariants)
+	key, ok := storeProfileImage(w, r, "banners", extension, outputType, encoded, avatar.BannerVariantKey, func(repo *repository.UserRepository, key *string) (*string, *string, error) {
+		return repo.UpdateBanner(userID, key, &variants)
+	}, avatar.BannerKeys)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"bannerUrl": avatar.BannerURL(&key),
+	})
+}
+
+// Remove a imagem de capa do perfil
+func DeleteBanner(w http.ResponseWriter, r *http.Request) {
+	userID, ok := avatarOwner(w, r)
+	if !ok {
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	previous, previousVariants, err := repository.NewUserRepository(db).UpdateBanner(userID, nil, nil)
+	if err != nil {
+		http.Error(w, "Erro ao remover imagem de capa", http.StatusInternalServerError)
+		return
+	}
+
+	if previous != nil {
+		store, err := storage.New()
+		if err != nil {
+			log.Println("Erro ao acessar armazenamento:", err)
+		} else {
+			deleteAvatarFiles(store, avatar.BannerKeys(*previous, previousVariants))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Remove a foto de perfil; o usuário volta a exibir o identicon
+func DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	userID, ok := avatarOwner(w, r)
+	if !ok {
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	previous, previousVariants, err := repository.NewUserRepository(db).UpdateAvatar(userID, nil, nil)
+	if err != nil {
+		http.Error(w, "Erro ao remover foto de perfil", http.StatusInternalServerError)
+		return
+	}
+
+	if previous != nil {
+		store, err := storage.New()
+		if err != nil {
+			log.Println("Erro ao acessar armazenamento:", err)
+		} else {
+			deleteAvatarFiles(store, avatar.Keys(*previous, previousVariants))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(avatarResponse(userID, nil, nil))
+}
+
+// Gera o avatar padrão do usuário. Não depende do banco: o desenho sai do próprio ID.
+func GetIdenticon(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	userID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	size := identiconDefaultSize
+	if value := r.URL.Query().Get("size"); value != "" {
+		size, err = strconv.Atoi(value)
+		if err != nil || size < 16 || size > 512 {
+			http.Error(w, "Tamanho inválido (entre 16 e 512)", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, avatar.Identicon(userID, size)); err != nil {
+		http.Error(w, "Erro ao gerar avatar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=604800, immutable")
+	w.Write(buf.Bytes())
+}
+
+// readProfileImage lê a imagem do campo "file" e identifica o formato em que será regravada
+func readProfileImage(w http.ResponseWriter, r *http.Request) (raw []byte, outputType, extension string, ok bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, config.MaxUploadBytes+multipartOverhead)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Arquivo maior que o limite permitido", http.StatusRequestEntityTooLarge)
+			return nil, "", "", false
+		}
+		http.Error(w, "Arquivo não enviado no campo \"file\"", http.StatusBadRequest)
+		return nil, "", "", false
+	}
+	defer file.Close()
+	defer r.MultipartForm.RemoveAll()
+
+	if header.Size > config.MaxUploadBytes {
+		http.Error(w, "Arquivo maior que o limite permitido", http.StatusRequestEntityTooLarge)
+		return nil, "", "", false
+	}
+
+	raw, err = io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Erro ao ler arquivo", http.StatusBadRequest)
+		return nil, "", "", false
+	}
+
+	outputType, extension, isImage := media.OutputFormat(http.DetectContentType(raw))
+	if !isImage {
+		http.Error(w, "A imagem deve estar em JPEG, PNG, GIF ou WebP", http.StatusUnsupportedMediaType)
+		return nil, "", "", false
+	}
+
+	return raw, outputType, extension, true
+}
+
+// processProfileImage executa o processamento no pool de imagens e aguarda o resultado
+func processProfileImage(w http.ResponseWriter, process func() ([]media.Encoded, error)) ([]media.Encoded, bool) {
+	var encoded []media.Encoded
+	var processErr error
+	err := media.Do(func() {
+		encoded, processErr = process()
+	})
+	if errors.Is(err, media.ErrQueueFull) {
+		http.Error(w, "Muitas imagens em processamento, tente novamente em instantes", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	if processErr != nil || len(encoded) == 0 {
+		if errors.Is(processErr, media.ErrInvalidCrop) || errors.Is(processErr, media.ErrTooManyPixels) {
+			http.Error(w, processErr.Error(), http.StatusBadRequest)
+			return nil, false
+		}
+		http.Error(w, "Não foi possível processar a imagem", http.StatusUnprocessableEntity)
+		return nil, false
+	}
+
+	return encoded, true
+}
+
+// storeProfileImage grava os tamanhos gerados, troca a chave no banco pelo update
+// informado e apaga os arquivos da imagem anterior
+func storeProfileImage(
+	w http.ResponseWriter,
+	r *http.Request,
+	prefix, extension, outputType string,
+	encoded []media.Encoded,
+	variantKey func(key, name string) string,
+	update func(repo *repository.UserRepository, key *string) (*string, *string, error),
+	previousKeys func(key string, variants *string) []string,
+) (string, bool) {
+	key, err := storage.NewKey(prefix, extension)
+	if err != nil {
+		http.Error(w, "Erro ao gerar nome do arquivo", http.StatusInternalServerError)
+		return "", false
+	}
+
+	store, err := storage.New()
+	if err != nil {
+		http.Error(w, "Erro ao acessar armazenamento", http.StatusInternalServerError)
+		return "", false
+	}
+
+	var written []string
+	for _, image := range encoded {
+		imageKey := variantKey(key, image.Name)
+
+		err := store.Put(r.Context(), imageKey, bytes.NewReader(image.Data), int64(len(image.Data)), outputType)
+		if err != nil {
+			deleteAvatarFiles(store, written)
+			http.Error(w, "Erro ao salvar arquivo", http.StatusInternalServerError)
+			return "", false
+		}
+		written = append(written, imageKey)
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		deleteAvatarFiles(store, written)
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return "", false
+	}
+	defer db.Close()
+
+	previous, previousVariants, err := update(repository.NewUserRepository(db), &key)
+	if err != nil {
+		deleteAvatarFiles(store, written)
+		http.Error(w, "Erro ao atualizar imagem do perfil", http.StatusInternalServerError)
+		return "", false
+	}
+
+	if previous != nil {
+		deleteAvatarFiles(store, previousKeys(*previous, previousVariants))
+	}
+
+	return key, true
+}
+
+// avatarOwner garante que o usuário só altere as imagens do próprio perfil
+func avatarOwner(w http.ResponseWriter, r *http.Request) (uint64, bool) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	targetID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return 0, false
+	}
+
+	if targetID != userID {
+		http.Error(w, "Sem permissão para alterar as imagens deste usuário", http.StatusForbidden)
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// avatarCrop lê o recorte opcional do formulário; os três campos vêm juntos ou nenhum
+func avatarCrop(r *http.Request) (*media.Crop, error) {
+	fields := []string{r.FormValue("x"), r.FormValue("y"), r.FormValue("size")}
+	if fields[0] == "" && fields[1] == "" && fields[2] == "" {
+		return nil, nil
+	}
+
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		value, err := strconv.Atoi(field)
+		if err != nil || value < 0 {
+			return nil, errors.New("recorte inválido: informe x, y e size como inteiros não negativos")
+		}
+		values[i] = value
+	}
+
+	return &media.Crop{X: values[0], Y: values[1], Size: values[2]}, nil
+}
+
+// avatarResponse lista o endereço padrão e o de cada tamanho gerado
+func avatarResponse(userID uint64, key, variants *string) map[string]interface{} {
+	sizes := map[string]string{}
+	if key != nil {
+		sizes = avatar.Sizes(*key, variants)
+	}
+
+	return map[string]interface{}{
+		"avatarUrl": avatar.URL(userID, key, variants),
+		"sizes":     sizes,
+	}
+}
+
+// deleteAvatarFiles apaga arquivos de uma imagem do perfil que deixou de ser usada. Falhas só são
+// registradas: a foto já não é referenciada e não deve impedir a resposta.
+func deleteAvatarFiles(store storage.Storage, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(context.Background(), key); err != nil {
+			log.Println("Erro ao remover arquivo de foto de perfil:", err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Lista o catálogo de conquistas com o total de usuários que tem cada uma
+func GetBadges(w http.ResponseWriter, r *http.Request) {
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	badges, err := repository.NewBadgesRepository(db).GetAll()
+	if err != nil {
+		http.Error(w, "Erro ao buscar conquistas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(badges)
+}
+
+// Lista quem recebeu uma conquista, da concessão mais recente para a mais antiga
+func GetBadgeHolders(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	slug := mux.Vars(r)["slug"]
+	if _, ok := model.FindBadge(slug); !ok {
+		http.Error(w, "Conquista não encontrada", http.StatusNotFound)
+		return
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	holders, err := repository.NewBadgesRepository(db).GetHolders(userID, slug, limit, offset)
+	if err != nil {
+		http.Error(w, "Erro ao buscar usuários com a conquista", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holders)
+}
+
+// Concede uma conquista manual a um usuário; apenas administradores
+func GrantBadge(w http.ResponseWriter, r *http.Request) {
+	manageBadge(w, r, "Conquista concedida", func(repo *repository.BadgesRepository, adminID, userID uint64, slug string) error {
+		return repo.Grant(userID, slug, adminID)
+	})
+}
+
+// Retira uma conquista manual de um usuário; apenas administradores
+func RevokeBadge(w http.ResponseWriter, r *http.Request) {
+	manageBadge(w, r, "Conquista retirada", func(repo *repository.BadgesRepository, _, userID uint64, slug string) error {
+		return repo.Revoke(userID, slug)
+	})
+}
+
+// manageBadge confirma que o usuário autenticado é administrador, que a conquista é
+// manual e que o usuário existe antes de executar a ação
+func manageBadge(w http.ResponseWriter, r *http.Request, message string, action func(repo *repository.BadgesRepository, adminID, userID uint64, slug string) error) {
+	adminID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	userID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	badge, ok := model.FindBadge(params["slug"])
+	if !ok {
+		http.Error(w, "Conquista não encontrada", http.StatusNotFound)
+		return
+	}
+	if !badge.Manual {
+		http.Error(w, "Conquistas automáticas não podem ser concedidas ou retiradas manualmente", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	usersRepo := repository.NewUserRepository(db)
+
+	role, err := usersRepo.GetRole(adminID)
+	if err != nil {
+		http.Error(w, "Erro ao verificar permissões", http.StatusInternalServerError)
+		return
+	}
+
+	if !model.IsAdmin(role) {
+		http.Error(w, "Apenas administradores podem gerenciar conquistas", http.StatusForbidden)
+		return
+	}
+
+	user, err := usersRepo.GetByID(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar usuário", http.StatusInternalServerError)
+		return
+	}
+	if user.ID == 0 {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	err = action(repository.NewBadgesRepository(db), adminID, userID, badge.Slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "O usuário não tem esta conquista", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao atualizar conquistas", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": message,
+	})
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Bloqueia o usuário informado na rota
+func BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	blockedID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	if blockedID == userID {
+		http.Error(w, "Você não pode bloquear você mesmo", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewUserRepository(db).Block(userID, blockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao bloquear usuário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Usuário bloqueado",
+	})
+}
+
+// Desbloqueia o usuário informado na rota
+func UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	blockedID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewUserRepository(db).Unblock(userID, blockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Bloqueio não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao desbloquear usuário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Usuário desbloqueado",
+	})
+}
+
+// Lista os usuários bloqueados pelo usuário autenticado
+func GetBlockedUsers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	blocked, err := repository.NewUserRepository(db).GetBlocked(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar bloqueios", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(publicProfiles(blocked))
+}
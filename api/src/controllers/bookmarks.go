@@ -0,0 +1,483 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Salva um post nos favoritos do usuário autenticado
+func AddBookmark(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	if !ensurePostVisible(w, repository.NewPostsRepository(db), userID, postID) {
+		return
+	}
+
+	if err := repository.NewBookmarksRepository(db).AddBookmark(userID, postID); err != nil {
+		http.Error(w, "Erro ao salvar post", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Post salvo nos favoritos",
+	})
+}
+
+// Remove um post dos favoritos; funciona mesmo se o post ficou indisponível
+func RemoveBookmark(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewBookmarksRepository(db).RemoveBookmark(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "O post não está nos favoritos", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao remover post dos favoritos", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Post removido dos favoritos",
+	})
+}
+
+// Lista os favoritos do usuário autenticado na ordem escolhida por ele
+func GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	bookmarks, err := repository.NewBookmarksRepository(db).GetBookmarks(userID, limit, offset)
+	if err != nil {
+		http.Error(w, "Erro ao buscar favoritos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bookmarks)
+}
+
+// Reordena os favoritos do usuário autenticado
+func ReorderBookmarks(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	order, ok := readPostOrder(w, r)
+	if !ok {
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewBookmarksRepository(db).ReorderBookmarks(userID, order.PostIDs)
+	if !reorderDone(w, err) {
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Favoritos reordenados",
+	})
+}
+
+// Cria uma coleção do usuário autenticado
+func CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	input, ok := readCollectionInput(w, r, true)
+	if !ok {
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewBookmarksRepository(db)
+
+	collectionID, err := repo.CreateCollection(userID, input)
+	if err != nil {
+		http.Error(w, "Erro ao criar coleção", http.StatusInternalServerError)
+		return
+	}
+
+	collection, err := repo.GetCollection(collectionID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar coleção", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(collection)
+}
+
+// Lista as coleções do usuário autenticado, públicas e privadas
+func GetMyCollections(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	collections, err := repository.NewBookmarksRepository(db).GetCollections(userID, true)
+	if err != nil {
+		http.Error(w, "Erro ao buscar coleções", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collections)
+}
+
+// Lista as coleções públicas de um usuário (todas, se for o próprio)
+func GetUserCollections(w http.ResponseWriter, r *http.Request) {
+	viewerID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	userID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	if userID != viewerID {
+		// Com bloqueio entre os dois, o usuário simplesmente "não existe"
+		blocked, err := repository.NewUserRepository(db).IsBlocked(viewerID, userID)
+		if err != nil {
+			http.Error(w, "Erro ao buscar usuário", http.StatusInternalServerError)
+			return
+		}
+		if blocked {
+			http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+			return
+		}
+	}
+
+	collections, err := repository.NewBookmarksRepository(db).GetCollections(userID, userID == viewerID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar coleções", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(collections)
+}
+
+// Busca uma coleção e seus posts. Coleções privadas só existem para o dono.
+func GetCollection(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	withCollection(w, r, false, func(db *sql.DB, userID uint64, collection model.Collection) {
+		repo := repository.NewBookmarksRepository(db)
+
+		posts, err := repo.GetCollectionPosts(userID, collection, limit, offset)
+		if err != nil {
+			http.Error(w, "Erro ao buscar posts da coleção", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"collection": collection,
+			"posts":      posts,
+		})
+	})
+}
+
+// Renomeia a coleção ou altera sua visibilidade
+func UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	input, ok := readCollectionInput(w, r, false)
+	if !ok {
+		return
+	}
+
+	withCollection(w, r, true, func(db *sql.DB, _ uint64, collection model.Collection) {
+		repo := repository.NewBookmarksRepository(db)
+
+		if err := repo.UpdateCollection(collection.ID, input); err != nil {
+			http.Error(w, "Erro ao atualizar coleção", http.StatusInternalServerError)
+			return
+		}
+
+		updated, err := repo.GetCollection(collection.ID)
+		if err != nil {
+			http.Error(w, "Erro ao buscar coleção", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(updated)
+	})
+}
+
+// Exclui a coleção; os posts continuam nos favoritos
+func DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	withCollection(w, r, true, func(db *sql.DB, _ uint64, collection model.Collection) {
+		repo := repository.NewBookmarksRepository(db)
+
+		if err := repo.DeleteCollection(collection.ID); err != nil {
+			http.Error(w, "Erro ao excluir coleção", http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Coleção excluída",
+		})
+	})
+}
+
+// Adiciona um post à coleção
+func AddToCollection(w http.ResponseWriter, r *http.Request) {
+	postID, err := strconv.ParseUint(mux.Vars(r)["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	withCollection(w, r, true, func(db *sql.DB, userID uint64, collection model.Collection) {
+		repo := repository.NewBookmarksRepository(db)
+
+		if !ensurePostVisible(w, repository.NewPostsRepository(db), userID, postID) {
+			return
+		}
+
+		if err := repo.AddToCollection(collection.ID, postID); err != nil {
+			http.Error(w, "Erro ao adicionar post à coleção", http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Post adicionado à coleção",
+		})
+	})
+}
+
+// Remove um post da coleção; funciona mesmo se o post ficou indisponível
+func RemoveFromCollection(w http.ResponseWriter, r *http.Request) {
+	postID, err := strconv.ParseUint(mux.Vars(r)["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	withCollection(w, r, true, func(db *sql.DB, _ uint64, collection model.Collection) {
+		repo := repository.NewBookmarksRepository(db)
+
+		err := repo.RemoveFromCollection(collection.ID, postID)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "O post não está na coleção", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao remover post da coleção", http.StatusInternalServerError)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Post removido da coleção",
+		})
+	})
+}
+
+// Reordena os posts da coleção
+func ReorderCollection(w http.ResponseWriter, r *http.Request) {
+	order, ok := readPostOrder(w, r)
+	if !ok {
+		return
+	}
+
+	withCollection(w, r, true, func(db *sql.DB, _ uint64, collection model.Collection) {
+		repo := repository.NewBookmarksRepository(db)
+
+		if !reorderDone(w, repo.ReorderCollection(collection.ID, order.PostIDs)) {
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Coleção reordenada",
+		})
+	})
+}
+
+// withCollection busca a coleção do parâmetro {id} e executa a ação. Coleções
+// privadas de outros usuários, ou de usuários com bloqueio, respondem 404; com
+// ownerOnly, coleções públicas de outros usuários respondem 403.
+func withCollection(w http.ResponseWriter, r *http.Request, ownerOnly bool, action func(db *sql.DB, userID uint64, collection model.Collection)) {
+	userID := r.Context().Value("userID").(uint64)
+
+	collectionID, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	collection, err := repository.NewBookmarksRepository(db).GetCollection(collectionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Coleção não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar coleção", http.StatusInternalServerError)
+		return
+	}
+
+	if collection.UserID != userID {
+		if !collection.Public {
+			http.Error(w, "Coleção não encontrada", http.StatusNotFound)
+			return
+		}
+
+		blocked, err := repository.NewUserRepository(db).IsBlocked(userID, collection.UserID)
+		if err != nil {
+			http.Error(w, "Erro ao buscar coleção", http.StatusInternalServerError)
+			return
+		}
+		if blocked {
+			http.Error(w, "Coleção não encontrada", http.StatusNotFound)
+			return
+		}
+
+		if ownerOnly {
+			http.Error(w, "Apenas o dono pode alterar a coleção", http.StatusForbidden)
+			return
+		}
+	}
+
+	action(db, userID, collection)
+}
+
+// readCollectionInput lê e valida o corpo de criação ou atualização de uma coleção
+func readCollectionInput(w http.ResponseWriter, r *http.Request, create bool) (model.CollectionInput, bool) {
+	var input model.CollectionInput
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Erro ao ler corpo da requisição", http.StatusBadRequest)
+		return input, false
+	}
+
+	if err := json.Unmarshal(body, &input); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return input, false
+	}
+
+	if err := input.Prepare(create); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return input, false
+	}
+
+	return input, true
+}
+
+// readPostOrder lê a nova ordem dos posts salvos
+func readPostOrder(w http.ResponseWriter, r *http.Request) (model.PostOrder, bool) {
+	var order model.PostOrder
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Erro ao ler corpo da requisição", http.StatusBadRequest)
+		return order, false
+	}
+
+	if err := json.Unmarshal(body, &order); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return order, false
+	}
+
+	if len(order.PostIDs) == 0 {
+		http.Error(w, "Informe postIds", http.StatusBadRequest)
+		return order, false
+	}
+
+	return order, true
+}
+
+// reorderDone responde o erro da reordenação, se houver, e informa se ela foi concluída
+func reorderDone(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, repository.ErrPostNotSaved) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if err != nil {
+		http.Error(w, "Erro ao reordenar posts", http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Busca as regras de comentário de um post
+func GetCommentSettings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	settings, err := repo.GetCommentSettings(postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar regras de comentário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// Bloqueia ou libera os comentários de um post do usuário autenticado e define quem pode comentar
+func UpdateCommentSettings(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Erro ao ler corpo da requisição", http.StatusBadRequest)
+		return
+	}
+
+	var settings model.CommentSettings
+	if err := json.Unmarshal(body, &settings); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := settings.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	post, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
+		return
+	}
+
+	if post.AuthorID != userID {
+		http.Error(w, "Apenas o autor do post pode alterar as regras de comentário", http.StatusForbidden)
+		return
+	}
+
+	if err := repo.UpdateCommentSettings(postID, settings); err != nil {
+		http.Error(w, "Erro ao atualizar regras de comentário", http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := repo.GetCommentSettings(postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar regras de comentário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// Oculta um comentário em um post do usuário autenticado
+func HideComment(w http.ResponseWriter, r *http.Request) {
+	moderateComment(w, r, "Comentário ocultado", "O comentário já está oculto", func(repo *repository.CommentsRepository, commentID uint64) error {
+		return repo.SetHidden(commentID, true)
+	})
+}
+
+// Volta a exibir um comentário ocultado
+func UnhideComment(w http.ResponseWriter, r *http.Request) {
+	moderateComment(w, r, "Comentário exibido novamente", "O comentário não está oculto", func(repo *repository.CommentsRepository, commentID uint64) error {
+		return repo.SetHidden(commentID, false)
+	})
+}
+
+// Fixa um comentário no topo de um post do usuário autenticado
+func PinComment(w http.ResponseWriter, r *http.Request) {
+	moderateComment(w, r, "Comentário fixado", "Apenas comentários de primeiro nível e não ocultos podem ser fixados", func(repo *repository.CommentsRepository, commentID uint64) error {
+		return repo.Pin(commentID)
+	})
+}
+
+// Desfixa o comentário
+func UnpinComment(w http.ResponseWriter, r *http.Request) {
+	moderateComment(w, r, "Comentário desfixado", "O comentário não está fixado", func(repo *repository.CommentsRepository, commentID uint64) error {
+		return repo.Unpin(commentID)
+	})
+}
+
+// moderateComment executa uma ação de moderação depois de confirmar que o comentário
+// está ativo e que o usuário autenticado é o autor do post. Quando a ação não se aplica
+// ao estado atual do comentário (sql.ErrNoRows), responde 409 com a mensagem conflict.
+func moderateComment(w http.ResponseWriter, r *http.Request, message, conflict string, action func(*repository.CommentsRepository, uint64) error) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewCommentsRepository(db)
+
+	_, postAuthorID, err := repo.GetModerationInfo(commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	if postAuthorID != userID {
+		http.Error(w, "Apenas o autor do post pode moderar seus comentários", http.StatusForbidden)
+		return
+	}
+
+	err = action(repo, commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, conflict, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao moderar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": message,
+	})
+}
@@ -0,0 +1,342 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/model"
+	"api/src/render"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Cria um rascunho, aceitando conteúdo parcial
+func CreateDraft(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	var draft model.DraftPatch
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil {
+		http.Error(w, "Erro no body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := draft.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar rascunho: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	post := model.Post{
+		AuthorID:   userID,
+		Visibility: model.VisibilityPublic,
+		Type:       model.PostTypePost,
+		Status:     model.PostStatusDraft,
+	}
+	if draft.Title != nil {
+		post.Title = *draft.Title
+	}
+	if draft.Content != nil {
+		post.Content = *draft.Content
+	}
+	if draft.Visibility != nil {
+		post.Visibility = *draft.Visibility
+	}
+	if draft.Type != nil {
+		post.Type = *draft.Type
+	}
+	post.QuotedPostID = draft.QuotedPostID
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensureQuotable(w, repo, userID, post.QuotedPostID) {
+		return
+	}
+
+	postID, err := repo.CreateDraft(post)
+	if err != nil {
+		http.Error(w, "Erro ao criar rascunho", http.StatusInternalServerError)
+		return
+	}
+
+	post.ID = postID
+	post.ContentHTML = render.Post(postID, post.Content)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(post)
+}
+
+// Lista os rascunhos do usuário autenticado
+func GetDrafts(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	drafts, err := repo.GetDrafts(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar rascunhos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(drafts)
+}
+
+// Salva automaticamente o rascunho, alterando apenas os campos enviados
+func AutosaveDraft(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var draft model.DraftPatch
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil {
+		http.Error(w, "Erro no body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := draft.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar rascunho: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	current, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && current.AuthorID != userID) {
+		http.Error(w, "Rascunho não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar rascunho", http.StatusInternalServerError)
+		return
+	}
+
+	if !ensureQuotable(w, repo, userID, draft.QuotedPostID) {
+		return
+	}
+
+	// Post agendado será publicado sem nova validação, então precisa continuar válido
+	if current.Status == model.PostStatusScheduled {
+		if err := draft.Apply(&current); err != nil {
+			http.Error(w, "Erro ao validar post: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = repo.SaveDraft(userID, postID, draft)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Rascunho não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao salvar rascunho", http.StatusInternalServerError)
+		return
+	}
+
+	saved, err := repo.GetByID(userID, postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar rascunho", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// Publica um rascunho após validar o post completo
+func PublishPost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	post, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && post.AuthorID != userID) {
+		http.Error(w, "Rascunho não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar rascunho", http.StatusInternalServerError)
+		return
+	}
+
+	if post.Status == model.PostStatusPublished {
+		http.Error(w, "O post já foi publicado", http.StatusConflict)
+		return
+	}
+
+	if err := post.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar post: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// O post citado pode ter deixado de ser compartilhável desde que o rascunho foi salvo
+	if !ensureQuotable(w, repo, userID, post.QuotedPostID) {
+		return
+	}
+
+	if err := repo.Publish(postID, post); err != nil {
+		http.Error(w, "Erro ao publicar post", http.StatusInternalServerError)
+		return
+	}
+
+	published, err := repo.GetPostWithLikeInfo(userID, postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar post publicado", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(published)
+}
+
+// Agenda ou reagenda a publicação de um rascunho
+func SchedulePost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var schedule model.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+		http.Error(w, "Erro no body", http.StatusBadRequest)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := schedule.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	post, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && post.AuthorID != userID) {
+		http.Error(w, "Rascunho não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar rascunho", http.StatusInternalServerError)
+		return
+	}
+
+	if post.Status == model.PostStatusPublished {
+		http.Error(w, "O post já foi publicado", http.StatusConflict)
+		return
+	}
+
+	// O post será publicado sem intervenção do autor, então precisa estar válido agora
+	if err := post.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar post: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !ensureQuotable(w, repo, userID, post.QuotedPostID) {
+		return
+	}
+
+	if err := repo.Schedule(postID, post, schedule.PublishAt); err != nil {
+		http.Error(w, "Erro ao agendar post", http.StatusInternalServerError)
+		return
+	}
+
+	scheduled, err := repo.GetByID(userID, postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar post agendado", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scheduled)
+}
+
+// Cancela o agendamento; o post volta para os rascunhos
+func CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	err = repo.CancelSchedule(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Agendamento não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao cancelar agendamento", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Agendamento cancelado",
+	})
+}
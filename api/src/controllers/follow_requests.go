@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Lista os pedidos recebidos para seguir o usuário autenticado
+func GetIncomingRequests(w http.ResponseWriter, r *http.Request) {
+	listFollowRequests(w, r, repository.UserRepository.IncomingRequests)
+}
+
+// Lista os pedidos enviados pelo usuário autenticado que ainda aguardam resposta
+func GetOutgoingRequests(w http.ResponseWriter, r *http.Request) {
+	listFollowRequests(w, r, repository.UserRepository.OutgoingRequests)
+}
+
+// Aprova o pedido do usuário informado na rota
+func ApproveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	answerFollowRequest(w, r, repository.UserRepository.ApproveRequest, "Solicitação aprovada")
+}
+
+// Recusa o pedido do usuário informado na rota
+func RejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	answerFollowRequest(w, r, repository.UserRepository.RejectRequest, "Solicitação recusada")
+}
+
+// Remove um seguidor do usuário autenticado
+func RemoveFollower(w http.ResponseWriter, r *http.Request) {
+	answerFollowRequest(w, r, repository.UserRepository.RemoveFollower, "Seguidor removido")
+}
+
+func listFollowRequests(w http.ResponseWriter, r *http.Request, list func(repository.UserRepository, uint64) ([]model.FollowRequest, error)) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	requests, err := list(*repository.NewUserRepository(db), userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar solicitações", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// answerFollowRequest aplica a ação entre o usuário autenticado e o da rota
+func answerFollowRequest(w http.ResponseWriter, r *http.Request, action func(repository.UserRepository, uint64, uint64) error, message string) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	otherID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = action(*repository.NewUserRepository(db), userID, otherID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Solicitação ou seguidor não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao processar solicitação", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": message,
+	})
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Silencia o usuário da rota. O corpo é opcional: {"expiresAt": "..."} limita a duração.
+// Chamar de novo para uma conta já silenciada altera a expiração.
+func MuteUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	mutedID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	if mutedID == userID {
+		http.Error(w, "Você não pode silenciar você mesmo", http.StatusBadRequest)
+		return
+	}
+
+	var options model.MuteOptions
+	if err := json.NewDecoder(r.Body).Decode(&options); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := options.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewMutesRepository(db).MuteUser(userID, mutedID, options.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao silenciar usuário", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Usuário silenciado",
+		"expiresAt": options.ExpiresAt,
+	})
+}
+
+// Encerra o silêncio do usuário da rota
+func UnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	mutedID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewMutesRepository(db).UnmuteUser(userID, mutedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Usuário não está silenciado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao remover silêncio", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Lista as contas silenciadas pelo usuário autenticado
+func GetMutedUsers(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	muted, err := repository.NewMutesRepository(db).GetMutedUsers(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar contas silenciadas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(muted)
+}
+
+// Lista as palavras silenciadas pelo usuário autenticado
+func GetMutedKeywords(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	keywords, err := repository.NewMutesRepository(db).GetKeywords(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar palavras silenciadas", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(keywords)
+}
+
+// Silencia uma palavra, frase ou hashtag
+func CreateMutedKeyword(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	var keyword model.MutedKeyword
+	if err := json.NewDecoder(r.Body).Decode(&keyword); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := keyword.Prepare(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewMutesRepository(db)
+
+	keywordID, err := repo.CreateKeyword(userID, keyword)
+	if err != nil {
+		http.Error(w, "Erro ao silenciar palavra", http.StatusInternalServerError)
+		return
+	}
+
+	saved, err := repo.GetKeyword(userID, keywordID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar palavra silenciada", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// Altera uma palavra silenciada (frase, opções de busca ou expiração)
+func UpdateMutedKeyword(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	keywordID, err := strconv.ParseUint(params["keywordId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	var keyword model.MutedKeyword
+	if err := json.NewDecoder(r.Body).Decode(&keyword); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := keyword.Prepare(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewMutesRepository(db)
+
+	err = repo.UpdateKeyword(userID, keywordID, keyword)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Palavra silenciada não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao alterar palavra silenciada", http.StatusInternalServerError)
+		return
+	}
+
+	saved, err := repo.GetKeyword(userID, keywordID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar palavra silenciada", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// Remove uma palavra silenciada
+func DeleteMutedKeyword(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	keywordID, err := strconv.ParseUint(params["keywordId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewMutesRepository(db).DeleteKeyword(userID, keywordID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Palavra silenciada não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao remover palavra silenciada", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
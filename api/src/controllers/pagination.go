@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// pagination lê ?limit= e ?offset= da URL, com limite padrão e máximo
+func pagination(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageSize
+
+	if value := r.URL.Query().Get("limit"); value != "" {
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxPageSize {
+			return 0, 0, errors.New("limit deve estar entre 1 e 100")
+		}
+	}
+
+	if value := r.URL.Query().Get("offset"); value != "" {
+		offset, err = strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset inválido")
+		}
+	}
+
+	return limit, offset, nil
+}
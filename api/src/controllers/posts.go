@@ -2,13 +2,19 @@ package controllers
 
 import (
 	"api/src/auth"
+	"api/src/config"
 	"api/src/database"
 	"api/src/model"
+	"api/src/render"
 	"api/src/repository"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -50,6 +56,10 @@ func CreatePost(w http.ResponseWriter, r *http.Request) {
 
 	repo := repository.NewPostsRepository(db)
 
+	if !ensureQuotable(w, repo, userID, post.QuotedPostID) {
+		return
+	}
+
 	postID, err := repo.Create(post)
 	if err != nil {
 		http.Error(w, "Erro ao criar post", http.StatusInternalServerError)
@@ -57,6 +67,16 @@ func CreatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	post.ID = postID
+	post.ContentHTML = render.Post(postID, post.Content)
+
+	if post.QuotedPostID != nil {
+		created, err := repo.GetByID(userID, postID)
+		if err != nil {
+			http.Error(w, "Erro ao buscar post criado", http.StatusInternalServerError)
+			return
+		}
+		post.QuotedPost = created.QuotedPost
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -73,8 +93,19 @@ func GetPosts(w http.ResponseWriter, r *http.Request) {
 
 	userID := r.Context().Value("userID").(uint64)
 
+	// ?type=question limita o feed às perguntas; ?unanswered=true, às ainda sem resposta aceita
+	query := r.URL.Query()
+	filter := model.FeedFilter{
+		Type:       strings.ToLower(strings.TrimSpace(query.Get("type"))),
+		Unanswered: query.Get("unanswered") == "true",
+	}
+	if filter.Type != "" && !model.ValidPostType(filter.Type) {
+		http.Error(w, "Tipo de post inválido", http.StatusBadRequest)
+		return
+	}
+
 	repo := repository.NewPostsRepository(db)
-	posts, err := repo.GetAll(userID)
+	posts, err := repo.GetAll(userID, filter)
 	if err != nil {
 		http.Error(w, "Erro ao buscar posts", http.StatusInternalServerError)
 		return
@@ -85,6 +116,8 @@ func GetPosts(w http.ResponseWriter, r *http.Request) {
 
 // GetPostByID busca um post pelo ID
 func GetPostByID(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
 	params := mux.Vars(r)
 	postID, err := strconv.ParseUint(params["postId"], 10, 64)
 	if err != nil {
@@ -101,14 +134,13 @@ func GetPostByID(w http.ResponseWriter, r *http.Request) {
 
 	repo := repository.NewPostsRepository(db)
 
-	post, err := repo.GetByID(postID)
-	if err != nil {
-		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
+	post, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
 		return
 	}
-
-	if post.ID == 0 {
-		http.Error(w, "Post não encontrado", http.StatusNotFound)
+	if err != nil {
+		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
 		return
 	}
 
@@ -119,14 +151,37 @@ func GetPostByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	reposts, quotes, err := repo.CountShares(postID)
+	if err != nil {
+		http.Error(w, "Erro ao contar reposts", http.StatusInternalServerError)
+		return
+	}
+
+	settings, err := repo.GetCommentSettings(postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar regras de comentário", http.StatusInternalServerError)
+		return
+	}
+
 	response := map[string]interface{}{
 		"id":              post.ID,
 		"title":           post.Title,
 		"content":         post.Content,
+		"content_html":    post.ContentHTML,
 		"author_id":       post.AuthorID,
 		"author_nickname": post.AuthorNickname,
+		"visibility":      post.Visibility,
+		"status":          post.Status,
 		"created_at":      post.CreatedAt,
+		"edited_at":       post.EditedAt,
+		"revision_count":  post.RevisionCount,
 		"likes":           likes,
+		"reposts":         reposts,
+		"quotes":          quotes,
+		"quoted_post_id":  post.QuotedPostID,
+		"quoted_post":     post.QuotedPost,
+		"comments_locked": settings.Locked,
+		"comment_policy":  settings.Policy,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -153,6 +208,13 @@ func UpdatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Mesma normalização da criação: "Public" é aceito como "public"
+	post.Visibility = strings.ToLower(strings.TrimSpace(post.Visibility))
+	if post.Visibility != "" && !model.ValidVisibility(post.Visibility) {
+		http.Error(w, "Visibilidade inválida", http.StatusBadRequest)
+		return
+	}
+
 	db, err := database.Connect()
 	if err != nil {
 		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
@@ -163,7 +225,11 @@ func UpdatePost(w http.ResponseWriter, r *http.Request) {
 	repo := repository.NewPostsRepository(db)
 
 	//valida se o post existe e se pertence ao user
-	savedPost, err := repo.GetByID(postID)
+	savedPost, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
 		return
@@ -174,7 +240,17 @@ func UpdatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := repo.Update(postID, post); err != nil {
+	// Cada edição vira uma revisão, então o post precisa continuar válido
+	post.AuthorID = savedPost.AuthorID
+	if post.Visibility == "" {
+		post.Visibility = savedPost.Visibility
+	}
+	if err := post.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar post: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := repo.Update(postID, userID, post); err != nil {
 		http.Error(w, "Erro ao atualizar post", http.StatusInternalServerError)
 		return
 	}
@@ -207,7 +283,11 @@ func DeletePost(w http.ResponseWriter, r *http.Request) {
 	repo := repository.NewPostsRepository(db)
 
 	//valida se o post existe e se pertence ao user
-	post, err := repo.GetByID(postID)
+	post, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
 		return
@@ -251,6 +331,10 @@ func LikePost(w http.ResponseWriter, r *http.Request) {
 
 	repo := repository.NewPostsRepository(db)
 
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
 	// Tenta inserir like
 	if err := repo.LikePost(userID, postID); err != nil {
 		http.Error(w, "Você já curtiu este post", http.StatusInternalServerError)
@@ -290,6 +374,10 @@ func UnlikePost(w http.ResponseWriter, r *http.Request) {
 
 	repo := repository.NewPostsRepository(db)
 
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
 	// Remove like
 	if err := repo.UnlikePost(userID, postID); err != nil {
 		http.Error(w, "Erro ao remover like", http.StatusInternalServerError)
@@ -332,8 +420,8 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if comment.Content == "" {
-		http.Error(w, "Comentário vazio", http.StatusBadRequest)
+	if err := comment.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar comentário: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -347,8 +435,43 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
+	postsRepo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, postsRepo, userID, postID) {
+		return
+	}
+
+	if err := postsRepo.CanComment(userID, postID); err != nil {
+		if errors.Is(err, repository.ErrCommentsLocked) || errors.Is(err, repository.ErrCommentsFollowersOnly) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, "Erro ao verificar permissão para comentar", http.StatusInternalServerError)
+		return
+	}
+
 	repo := repository.NewCommentsRepository(db)
 
+	// Respostas precisam de um comentário ativo do mesmo post e respeitam a profundidade máxima
+	comment.Depth = 0
+	if comment.ParentID != nil {
+		parentPostID, parentDepth, err := repo.GetThreadPosition(userID, *comment.ParentID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && parentPostID != postID) {
+			http.Error(w, "Comentário respondido não encontrado", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao buscar comentário respondido", http.StatusInternalServerError)
+			return
+		}
+
+		if parentDepth+1 > config.CommentMaxDepth {
+			http.Error(w, "Limite de respostas aninhadas atingido", http.StatusBadRequest)
+			return
+		}
+		comment.Depth = parentDepth + 1
+	}
+
 	commentID, err := repo.Create(comment)
 	if err != nil {
 		http.Error(w, "Erro ao criar comentário", http.StatusInternalServerError)
@@ -356,10 +479,13 @@ func CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	comment.ID = commentID
+	comment.ContentHTML = render.Comment(commentID, comment.Content)
 
 	json.NewEncoder(w).Encode(comment)
 }
 func GetCommentsByPost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
 	params := mux.Vars(r)
 	postID, err := strconv.ParseUint(params["postId"], 10, 64)
 	if err != nil {
@@ -374,8 +500,14 @@ func GetCommentsByPost(w http.ResponseWriter, r *http.Request) {
 	}
 	defer db.Close()
 
+	postsRepo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, postsRepo, userID, postID) {
+		return
+	}
+
 	repo := repository.NewCommentsRepository(db)
-	comments, err := repo.GetByCommentsPostID(postID)
+	comments, err := repo.GetByCommentsPostID(userID, postID)
 	if err != nil {
 		http.Error(w, "Erro ao buscar comentários", http.StatusInternalServerError)
 		return
@@ -408,18 +540,19 @@ func DeleteComment(w http.ResponseWriter, r *http.Request) {
 
 	repo := repository.NewCommentsRepository(db)
 
-	authorID, err := repo.GetAuthor(commentID)
+	// O autor do comentário e o autor do post podem excluí-lo
+	authorID, postAuthorID, err := repo.GetModerationInfo(commentID)
 	if err != nil {
 		http.Error(w, "Erro ao buscar autor", http.StatusBadRequest)
 		return
 	}
 
-	if authorID != userID {
+	if authorID != userID && postAuthorID != userID {
 		http.Error(w, "Não autorizado", http.StatusForbidden)
 		return
 	}
 
-	if err := repo.Delete(commentID); err != nil {
+	if err := repo.Delete(commentID, userID); err != nil {
 		http.Error(w, "Erro ao deletar comentário", http.StatusBadRequest)
 		return
 	}
@@ -428,3 +561,137 @@ func DeleteComment(w http.ResponseWriter, r *http.Request) {
 		"message": "Comentário deletado",
 	})
 }
+
+// Edita um comentário do usuário autenticado dentro do prazo de edição
+func UpdateComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Erro ao ler corpo da requisição", http.StatusBadRequest)
+		return
+	}
+
+	var comment model.Comment
+	if err := json.Unmarshal(body, &comment); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if err := comment.Prepare(); err != nil {
+		http.Error(w, "Erro ao validar comentário: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewCommentsRepository(db)
+
+	postID, authorID, createdAt, err := repo.GetEditInfo(commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	if authorID != userID {
+		http.Error(w, "Sem permissão para editar este comentário", http.StatusForbidden)
+		return
+	}
+
+	if time.Since(createdAt) > config.CommentEditWindow {
+		http.Error(w, "Prazo para edição do comentário encerrado", http.StatusForbidden)
+		return
+	}
+
+	// Editar segue as mesmas regras de comentar: o post precisa continuar visível
+	// (sem bloqueio do autor) e aberto a comentários
+	postsRepo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, postsRepo, userID, postID) {
+		return
+	}
+
+	if err := postsRepo.CanComment(userID, postID); err != nil {
+		if errors.Is(err, repository.ErrCommentsLocked) || errors.Is(err, repository.ErrCommentsFollowersOnly) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, "Erro ao verificar permissão para comentar", http.StatusInternalServerError)
+		return
+	}
+
+	if err := repo.Update(commentID, userID, comment.Content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Erro ao editar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	updated, err := repo.GetByID(userID, commentID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar comentário atualizado", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// ensureQuotable verifica o post citado, se houver: citações só podem embutir posts que
+// o autor vê e que podem ser compartilhados. Retorna true quando a requisição pode continuar.
+func ensureQuotable(w http.ResponseWriter, repo *repository.PostsRepository, userID uint64, quotedPostID *uint64) bool {
+	if quotedPostID == nil {
+		return true
+	}
+
+	err := repo.CheckShareable(userID, *quotedPostID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post citado não encontrado", http.StatusNotFound)
+		return false
+	}
+	if errors.Is(err, repository.ErrNotShareable) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return false
+	}
+	if err != nil {
+		http.Error(w, "Erro ao verificar post citado", http.StatusInternalServerError)
+		return false
+	}
+
+	return true
+}
+
+// ensurePostVisible responde 404 quando o post não existe ou não é visível para o usuário.
+// Retorna true quando a requisição pode continuar.
+func ensurePostVisible(w http.ResponseWriter, repo *repository.PostsRepository, userID, postID uint64) bool {
+	visible, err := repo.CanView(userID, postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
+		return false
+	}
+
+	if !visible {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Vota (+1 ou -1) em uma resposta de pergunta
+func VoteAnswer(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Erro ao ler corpo da requisição", http.StatusBadRequest)
+		return
+	}
+
+	var vote struct {
+		Value int `json:"value"`
+	}
+	if err := json.Unmarshal(body, &vote); err != nil {
+		http.Error(w, "JSON inválido", http.StatusBadRequest)
+		return
+	}
+
+	if vote.Value != 1 && vote.Value != -1 {
+		http.Error(w, "O voto deve ser 1 ou -1", http.StatusBadRequest)
+		return
+	}
+
+	answerAction(w, r, "Voto registrado", func(repo *repository.CommentsRepository, userID, commentID, answerAuthorID, _ uint64) (int, string) {
+		if answerAuthorID == userID {
+			return http.StatusForbidden, "Você não pode votar na sua própria resposta"
+		}
+		if err := repo.Vote(userID, commentID, vote.Value); err != nil {
+			return http.StatusInternalServerError, "Erro ao registrar voto"
+		}
+		return http.StatusOK, ""
+	})
+}
+
+// Remove o voto do usuário autenticado em uma resposta
+func RemoveAnswerVote(w http.ResponseWriter, r *http.Request) {
+	answerAction(w, r, "Voto removido", func(repo *repository.CommentsRepository, userID, commentID, _, _ uint64) (int, string) {
+		err := repo.Unvote(userID, commentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return http.StatusNotFound, "Voto não encontrado"
+		}
+		if err != nil {
+			return http.StatusInternalServerError, "Erro ao remover voto"
+		}
+		return http.StatusOK, ""
+	})
+}
+
+// Marca uma resposta como aceita; apenas quem perguntou pode aceitar
+func AcceptAnswer(w http.ResponseWriter, r *http.Request) {
+	answerAction(w, r, "Resposta aceita", func(repo *repository.CommentsRepository, userID, commentID, _, askerID uint64) (int, string) {
+		if askerID != userID {
+			return http.StatusForbidden, "Apenas quem perguntou pode aceitar uma resposta"
+		}
+		err := repo.Accept(commentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return http.StatusConflict, "Respostas ocultas não podem ser aceitas"
+		}
+		if err != nil {
+			return http.StatusInternalServerError, "Erro ao aceitar resposta"
+		}
+		return http.StatusOK, ""
+	})
+}
+
+// Desmarca a resposta aceita
+func UnacceptAnswer(w http.ResponseWriter, r *http.Request) {
+	answerAction(w, r, "Resposta desmarcada", func(repo *repository.CommentsRepository, userID, commentID, _, askerID uint64) (int, string) {
+		if askerID != userID {
+			return http.StatusForbidden, "Apenas quem perguntou pode desmarcar a resposta aceita"
+		}
+		err := repo.Unaccept(commentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return http.StatusConflict, "A resposta não está aceita"
+		}
+		if err != nil {
+			return http.StatusInternalServerError, "Erro ao desmarcar resposta"
+		}
+		return http.StatusOK, ""
+	})
+}
+
+// answerAction confirma que o comentário é uma resposta de pergunta visível para o
+// usuário autenticado e executa a ação, que devolve o status e a mensagem de erro
+func answerAction(w http.ResponseWriter, r *http.Request, message string, action func(repo *repository.CommentsRepository, userID, commentID, answerAuthorID, askerID uint64) (int, string)) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewCommentsRepository(db)
+
+	if !ensureCommentVisible(w, db, repo, userID, commentID) {
+		return
+	}
+
+	answerAuthorID, askerID, err := repo.GetAnswerInfo(commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, repository.ErrNotAnAnswer) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar resposta", http.StatusInternalServerError)
+		return
+	}
+
+	if status, errMessage := action(repo, userID, commentID, answerAuthorID, askerID); status != http.StatusOK {
+		http.Error(w, errMessage, status)
+		return
+	}
+
+	updated, err := repo.GetByID(userID, commentID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar resposta atualizada", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": message,
+		"answer":  updated,
+	})
+}
@@ -0,0 +1,267 @@
+package controllers
+
+import (
+	"api/src/config"
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Define a reação do usuário autenticado a um post, trocando a anterior se houver
+func ReactToPost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	emoji, ok := allowedReaction(w, params["emoji"], postReactions())
+	if !ok {
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	if err := repo.React(userID, postID, emoji); err != nil {
+		http.Error(w, "Erro ao reagir ao post", http.StatusInternalServerError)
+		return
+	}
+
+	updatedPost, err := repo.GetPostWithLikeInfo(userID, postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar post atualizado", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updatedPost)
+}
+
+// Lista, paginado, quem reagiu a um post; ?emoji= filtra por um emoji
+func GetPostReactions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	emoji := strings.TrimSpace(r.URL.Query().Get("emoji"))
+	if emoji != "" {
+		var ok bool
+		if emoji, ok = allowedReaction(w, emoji, postReactions()); !ok {
+			return
+		}
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	reactions, err := repo.GetReactions(userID, postID, emoji, limit, offset)
+	if err != nil {
+		http.Error(w, "Erro ao buscar reações", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// Adiciona uma reação do usuário autenticado a um comentário
+func ReactToComment(w http.ResponseWriter, r *http.Request) {
+	changeCommentReaction(w, r, true)
+}
+
+// Remove uma reação do usuário autenticado a um comentário
+func RemoveCommentReaction(w http.ResponseWriter, r *http.Request) {
+	changeCommentReaction(w, r, false)
+}
+
+// Lista, paginado, quem reagiu a um comentário; ?emoji= filtra por um emoji
+func GetCommentReactions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	emoji := strings.TrimSpace(r.URL.Query().Get("emoji"))
+	if emoji != "" {
+		var ok bool
+		if emoji, ok = allowedReaction(w, emoji, config.CommentReactions); !ok {
+			return
+		}
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewCommentsRepository(db)
+
+	if !ensureCommentVisible(w, db, repo, userID, commentID) {
+		return
+	}
+
+	reactions, err := repo.GetReactions(userID, commentID, emoji, limit, offset)
+	if err != nil {
+		http.Error(w, "Erro ao buscar reações", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reactions)
+}
+
+// changeCommentReaction adiciona ou remove a reação e responde com os totais atualizados
+func changeCommentReaction(w http.ResponseWriter, r *http.Request, add bool) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	emoji, ok := allowedReaction(w, params["emoji"], config.CommentReactions)
+	if !ok {
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewCommentsRepository(db)
+
+	if !ensureCommentVisible(w, db, repo, userID, commentID) {
+		return
+	}
+
+	if add {
+		if err := repo.React(userID, commentID, emoji); err != nil {
+			http.Error(w, "Erro ao reagir ao comentário", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		err := repo.Unreact(userID, commentID, emoji)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Reação não encontrada", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Erro ao remover reação", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	summary, err := repo.GetReactionSummary(userID, commentID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar reações", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// allowedReaction responde 400 quando o emoji não está entre as reações permitidas e,
+// quando está, retorna a forma configurada. O seletor de variação (U+FE0F) é ignorado
+// na comparação, então "❤" e "❤️" são a mesma reação.
+func allowedReaction(w http.ResponseWriter, emoji string, allowed []string) (string, bool) {
+	emoji = withoutVariationSelector(strings.TrimSpace(emoji))
+	for _, reaction := range allowed {
+		if withoutVariationSelector(reaction) == emoji {
+			return reaction, true
+		}
+	}
+
+	http.Error(w, "Reação não permitida. Use uma destas: "+strings.Join(allowed, " "), http.StatusBadRequest)
+	return "", false
+}
+
+func withoutVariationSelector(emoji string) string {
+	return strings.ReplaceAll(emoji, "\uFE0F", "")
+}
+
+// postReactions são as reações configuradas para os posts, sempre incluindo a do like
+func postReactions() []string {
+	if slices.Contains(config.PostReactions, model.LikeReaction) {
+		return config.PostReactions
+	}
+	return append([]string{model.LikeReaction}, config.PostReactions...)
+}
+
+// ensureCommentVisible responde 404 quando o comentário não está ativo, é de um usuário
+// com bloqueio em relação a quem consulta ou está em um post que ele não pode ver.
+// Retorna true quando a requisição pode continuar.
+func ensureCommentVisible(w http.ResponseWriter, db *sql.DB, repo *repository.CommentsRepository, userID, commentID uint64) bool {
+	postID, _, err := repo.GetThreadPosition(userID, commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+		return false
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar comentário", http.StatusInternalServerError)
+		return false
+	}
+
+	return ensurePostVisible(w, repository.NewPostsRepository(db), userID, postID)
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"api/src/model"
+	"api/src/render"
+	"encoding/json"
+	"net/http"
+)
+
+// Tamanho máximo do conteúdo aceito na pré-visualização
+const maxPreviewBytes = 1 << 20
+
+// Renderiza o markdown enviado sem salvar nada, para pré-visualização no editor
+func RenderPreview(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxPreviewBytes)
+	defer r.Body.Close()
+
+	var preview model.RenderPreview
+	if err := json.NewDecoder(r.Body).Decode(&preview); err != nil {
+		http.Error(w, "Erro no body", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"content_html": render.Markdown(preview.Content),
+	})
+}
+
+// Retorna o CSS do destaque de sintaxe dos blocos de código (temas claro e escuro)
+func HighlightStylesheet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write([]byte(render.Stylesheet()))
+}
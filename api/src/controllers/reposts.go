@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Reposta um post para os seguidores do usuário autenticado
+func RepostPost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	err = repo.CheckShareable(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, repository.ErrNotShareable) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao verificar post", http.StatusInternalServerError)
+		return
+	}
+
+	err = repo.Repost(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Você já repostou este post", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao repostar", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Post repostado",
+	})
+}
+
+// Desfaz o repost do usuário autenticado
+func UndoRepost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	err = repository.NewPostsRepository(db).Unrepost(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Repost não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao desfazer repost", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Repost desfeito",
+	})
+}
+
+// Lista quem repostou um post
+func GetPostReposts(w http.ResponseWriter, r *http.Request) {
+	listShares(w, r, func(repo *repository.PostsRepository, userID, postID uint64, limit, offset int) (interface{}, error) {
+		return repo.GetReposts(userID, postID, limit, offset)
+	})
+}
+
+// Lista as citações de um post
+func GetPostQuotes(w http.ResponseWriter, r *http.Request) {
+	listShares(w, r, func(repo *repository.PostsRepository, userID, postID uint64, limit, offset int) (interface{}, error) {
+		return repo.GetQuotes(userID, postID, limit, offset)
+	})
+}
+
+// listShares valida o post e a paginação e devolve a listagem paginada de reposts ou citações
+func listShares(w http.ResponseWriter, r *http.Request, list func(repo *repository.PostsRepository, userID, postID uint64, limit, offset int) (interface{}, error)) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	items, err := list(repo, userID, postID, limit, offset)
+	if err != nil {
+		http.Error(w, "Erro ao buscar compartilhamentos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/repository"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Lista o histórico de reputação de um usuário, do evento mais recente para o mais antigo
+func GetReputationHistory(w http.ResponseWriter, r *http.Request) {
+	viewerID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	userID, err := strconv.ParseUint(params["userId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	usersRepo := repository.NewUserRepository(db)
+
+	user, err := usersRepo.GetByID(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar usuário", http.StatusInternalServerError)
+		return
+	}
+
+	// Com bloqueio entre os dois, o usuário simplesmente "não existe"
+	blocked := false
+	if user.ID != 0 && user.ID != viewerID {
+		blocked, err = usersRepo.IsBlocked(viewerID, user.ID)
+		if err != nil {
+			http.Error(w, "Erro ao buscar usuário", http.StatusInternalServerError)
+			return
+		}
+	}
+	if user.ID == 0 || blocked {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+
+	events, err := repository.NewReputationRepository(db).GetHistory(userID, limit, offset)
+	if err != nil {
+		http.Error(w, "Erro ao buscar histórico de reputação", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
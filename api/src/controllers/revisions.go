@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"api/src/database"
+	"api/src/diff"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// Lista o histórico de revisões de um post
+func GetRevisions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	revisions, err := repo.GetRevisions(postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar revisões", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// Busca uma revisão específica de um post
+func GetRevision(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	revisionID, err := strconv.ParseUint(params["revisionId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID da revisão inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	revision, err := repo.GetRevision(postID, revisionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Revisão não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar revisão", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revision)
+}
+
+// Compara duas revisões de um post linha a linha (?from=&to=)
+func DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	fromID, err := strconv.ParseUint(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		http.Error(w, "Revisão de origem inválida", http.StatusBadRequest)
+		return
+	}
+
+	toID, err := strconv.ParseUint(r.URL.Query().Get("to"), 10, 64)
+	if err != nil {
+		http.Error(w, "Revisão de destino inválida", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	if !ensurePostVisible(w, repo, userID, postID) {
+		return
+	}
+
+	var to model.Revision
+
+	from, err := repo.GetRevision(postID, fromID)
+	if err == nil {
+		to, err = repo.GetRevision(postID, toID)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Revisão não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar revisões", http.StatusInternalServerError)
+		return
+	}
+
+	var content []model.DiffLine
+
+	title, err := diff.Lines(from.Title, to.Title)
+	if err == nil {
+		content, err = diff.Lines(from.Content, to.Content)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.RevisionDiff{
+		From:    from.ID,
+		To:      to.ID,
+		Title:   title,
+		Content: content,
+	})
+}
+
+// Restaura uma revisão antiga; a restauração gera uma nova revisão
+func RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	revisionID, err := strconv.ParseUint(params["revisionId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID da revisão inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewPostsRepository(db)
+
+	post, err := repo.GetByID(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar post", http.StatusInternalServerError)
+		return
+	}
+
+	if post.AuthorID != userID {
+		http.Error(w, "Sem permissão para editar este post", http.StatusForbidden)
+		return
+	}
+
+	revision, err := repo.GetRevision(postID, revisionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Revisão não encontrada", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao buscar revisão", http.StatusInternalServerError)
+		return
+	}
+
+	post.Title = revision.Title
+	post.Content = revision.Content
+
+	if err := repo.Update(postID, userID, post); err != nil {
+		http.Error(w, "Erro ao restaurar revisão", http.StatusInternalServerError)
+		return
+	}
+
+	restored, err := repo.GetPostWithLikeInfo(userID, postID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar post atualizado", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restored)
+}
+
+// Lista o histórico de edições de um comentário (somente moderadores)
+func GetCommentHistory(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar DB", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	role, err := repository.NewUserRepository(db).GetRole(userID)
+	if err != nil {
+		http.Error(w, "Erro ao verificar permissões", http.StatusInternalServerError)
+		return
+	}
+
+	if !model.IsModerator(role) {
+		http.Error(w, "Apenas moderadores podem ver o histórico de edições", http.StatusForbidden)
+		return
+	}
+
+	repo := repository.NewCommentsRepository(db)
+
+	if _, err := repo.GetPostID(commentID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Comentário não encontrado", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Erro ao buscar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	revisions, err := repo.GetHistory(commentID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar histórico", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
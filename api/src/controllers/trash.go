@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"api/src/config"
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// Lista os posts e comentários na lixeira do usuário autenticado
+func GetTrash(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewTrashRepository(db)
+
+	posts, err := repo.GetPosts(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar posts excluídos", http.StatusInternalServerError)
+		return
+	}
+
+	comments, err := repo.GetComments(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar comentários excluídos", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(model.Trash{
+		Posts:       posts,
+		Comments:    comments,
+		PurgeBefore: time.Now().Add(-config.TrashRetention),
+	})
+}
+
+// Restaura um post da lixeira
+func RestorePost(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	postID, err := strconv.ParseUint(params["postId"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewTrashRepository(db)
+
+	err = repo.RestorePost(userID, postID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Post não encontrado na lixeira", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao restaurar post", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Post restaurado com sucesso!",
+	})
+}
+
+// Restaura um comentário da lixeira
+func RestoreComment(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value("userID").(uint64)
+
+	params := mux.Vars(r)
+	commentID, err := strconv.ParseUint(params["id"], 10, 64)
+	if err != nil {
+		http.Error(w, "ID inválido", http.StatusBadRequest)
+		return
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		http.Error(w, "Erro ao conectar ao banco", http.StatusInternalServerError)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewTrashRepository(db)
+
+	err = repo.RestoreComment(userID, commentID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "Comentário não encontrado na lixeira", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Erro ao restaurar comentário", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Comentário restaurado",
+	})
+}
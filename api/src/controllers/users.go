@@ -7,6 +7,7 @@ import (
 	"api/src/repository"
 	"api/src/security"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -65,7 +66,7 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	repo := repository.NewUserRepository(db)
-	users, err := repo.GetAll(nameOrNick)
+	users, err := repo.GetAll(r.Context().Value("userID").(uint64), nameOrNick)
 	if err != nil {
 		http.Error(w, "Erro ao buscar usuários", http.StatusInternalServerError)
 		return
@@ -82,7 +83,7 @@ func GetUsers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(users)
+	json.NewEncoder(w).Encode(publicProfiles(users))
 }
 
 // Busca um usuário pelo ID
@@ -118,8 +119,39 @@ func GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// O próprio usuário recebe seus dados completos; os demais, só o perfil público
+	viewerID := r.Context().Value("userID").(uint64)
+	if user.ID == viewerID {
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(user)
+		return
+	}
+
+	// Com bloqueio entre os dois, o perfil simplesmente "não existe"
+	blocked, err := repo.IsBlocked(viewerID, user.ID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar usuário", http.StatusInternalServerError)
+		return
+	}
+	if blocked {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"message": "Usuário não encontrado",
+		})
+		return
+	}
+
+	relationship, err := repo.Relationship(viewerID, user.ID)
+	if err != nil {
+		http.Error(w, "Erro ao verificar follow", http.StatusInternalServerError)
+		return
+	}
+
+	profile := user.Public()
+	profile.Relationship = &relationship
+
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(user)
+	json.NewEncoder(w).Encode(profile)
 }
 
 // Atualiza os dados de um usuário
@@ -174,11 +206,14 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user.ID = userID
-	user.Password = ""
+	updated, err := repo.GetByID(userID)
+	if err != nil {
+		http.Error(w, "Erro ao buscar usuário", http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(user)
+	json.NewEncoder(w).Encode(updated)
 }
 
 // Deleta um usuário
@@ -251,10 +286,23 @@ func Follow(w http.ResponseWriter, r *http.Request) {
 	defer db.Close()
 
 	repo := repository.NewUserRepository(db)
-	if err := repo.Follow(followerId, userFollowedID); err != nil {
+	requested, err := repo.Follow(followerId, userFollowedID)
+	if errors.Is(err, repository.ErrFollowTargetNotFound) {
+		http.Error(w, "Usuário não encontrado", http.StatusNotFound)
+		return
+	}
+	if err != nil {
 		http.Error(w, "Erro ao seguir usuário: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	// Conta privada: o follow só vale depois de aprovado
+	if requested {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("Solicitação para seguir enviada"))
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Usuario seguido com sucesso"))
 }
@@ -366,7 +414,7 @@ func GetFollowers(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(followers)
+	json.NewEncoder(w).Encode(publicProfiles(followers))
 }
 
 // Retorna quem um determinado usuario esta seguindo(Quem você segue)
@@ -404,7 +452,7 @@ func GetFollowing(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(following)
+	json.NewEncoder(w).Encode(publicProfiles(following))
 }
 
 // Atualiza a senha do usuário
@@ -492,3 +540,12 @@ func UpdatePassword(w http.ResponseWriter, r *http.Request) {
 		"message": "Senha atualizada com sucesso!",
 	})
 }
+
+// publicProfiles converte listagens de usuários para o perfil público, sem emails
+func publicProfiles(users []model.User) []model.PublicProfile {
+	profiles := make([]model.PublicProfile, 0, len(users))
+	for _, user := range users {
+		profiles = append(profiles, user.Public())
+	}
+	return profiles
+}
@@ -0,0 +1,108 @@
+// Diff por linhas entre dois textos
+package diff
+
+import (
+	"api/src/model"
+	"errors"
+	"strings"
+)
+
+// Operações de uma linha do diff
+const (
+	OpEqual  = "equal"
+	OpInsert = "insert"
+	OpDelete = "delete"
+)
+
+// maxCells limita o tamanho da tabela da maior subsequência comum (linhas
+// alteradas do primeiro texto × linhas alteradas do segundo), e com isso a
+// memória usada pela comparação
+const maxCells = 4_000_000
+
+var ErrTooLarge = errors.New("os textos são grandes demais para comparar")
+
+// Lines compara os textos linha a linha usando a maior subsequência comum.
+// Retorna ErrTooLarge se o trecho alterado for grande demais para comparar.
+func Lines(before, after string) ([]model.DiffLine, error) {
+	a := splitLines(before)
+	b := splitLines(after)
+
+	// O início e o fim em comum não precisam entrar na tabela
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	middleA := a[prefix : len(a)-suffix]
+	middleB := b[prefix : len(b)-suffix]
+
+	if (len(middleA)+1)*(len(middleB)+1) > maxCells {
+		return nil, ErrTooLarge
+	}
+
+	lines := []model.DiffLine{}
+	for _, line := range a[:prefix] {
+		lines = append(lines, model.DiffLine{Op: OpEqual, Text: line})
+	}
+	lines = append(lines, middle(middleA, middleB)...)
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, model.DiffLine{Op: OpEqual, Text: line})
+	}
+
+	return lines, nil
+}
+
+// middle compara o trecho entre o início e o fim em comum
+func middle(a, b []string) []model.DiffLine {
+	// lcs[i][j] guarda o tamanho da maior subsequência comum entre a[i:] e b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := []model.DiffLine{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, model.DiffLine{Op: OpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, model.DiffLine{Op: OpDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, model.DiffLine{Op: OpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, model.DiffLine{Op: OpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, model.DiffLine{Op: OpInsert, Text: b[j]})
+	}
+
+	return lines
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,100 @@
+package media
+
+import (
+	"api/src/config"
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"api/src/storage"
+	"bytes"
+	"context"
+	"log"
+)
+
+// ProcessAttachment gera e grava os tamanhos de uma imagem anexada. O maior tamanho é
+// gravado na chave principal do anexo; os demais recebem o nome da variante como sufixo.
+func ProcessAttachment(attachmentID uint64, key, outputType string, raw []byte) {
+	db, err := database.Connect()
+	if err != nil {
+		log.Println("Erro ao conectar ao banco para processar imagem:", err)
+		return
+	}
+	defer db.Close()
+
+	repo := repository.NewAttachmentsRepository(db)
+
+	store, err := storage.New()
+	if err != nil {
+		log.Println("Erro ao acessar armazenamento para processar imagem:", err)
+		if err := repo.MarkFailed(attachmentID); err != nil {
+			log.Println("Erro ao marcar falha no anexo:", err)
+		}
+		return
+	}
+
+	variants, width, height, err := storeVariants(store, key, outputType, raw)
+	if err == nil {
+		err = repo.MarkReady(attachmentID, width, height, variants)
+	}
+	if err != nil {
+		log.Printf("Erro ao processar imagem do anexo %d: %v\n", attachmentID, err)
+
+		// Só a chave principal fica registrada no anexo; as variantes já gravadas
+		// seriam esquecidas quando ele fosse removido
+		removeVariants(store, key, variants)
+		if err := repo.MarkFailed(attachmentID); err != nil {
+			log.Println("Erro ao marcar falha no anexo:", err)
+		}
+	}
+}
+
+// storeVariants decodifica a imagem, gera os tamanhos configurados e grava cada um.
+// Em caso de erro, retorna também as variantes gravadas até ali.
+func storeVariants(store storage.Storage, key, outputType string, raw []byte) ([]model.AttachmentVariant, int, int, error) {
+	img, err := Decode(raw)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	encoded, err := Variants(img, outputType, config.ImageVariants)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	variants := make([]model.AttachmentVariant, 0, len(encoded))
+	for i, image := range encoded {
+		variantKey := storage.VariantKey(key, image.Name)
+		if i == len(encoded)-1 {
+			variantKey = key
+		}
+
+		err := store.Put(context.Background(), variantKey, bytes.NewReader(image.Data), int64(len(image.Data)), outputType)
+		if err != nil {
+			return variants, 0, 0, err
+		}
+
+		variants = append(variants, model.AttachmentVariant{
+			Name:       image.Name,
+			StorageKey: variantKey,
+			Width:      image.Width,
+			Height:     image.Height,
+			Size:       int64(len(image.Data)),
+		})
+	}
+
+	largest := encoded[len(encoded)-1]
+	return variants, largest.Width, largest.Height, nil
+}
+
+// removeVariants apaga do armazenamento as variantes gravadas, exceto a chave
+// principal, que continua registrada e é apagada junto com o anexo
+func removeVariants(store storage.Storage, key string, variants []model.AttachmentVariant) {
+	for _, variant := range variants {
+		if variant.StorageKey == key {
+			continue
+		}
+		if err := store.Delete(context.Background(), variant.StorageKey); err != nil {
+			log.Println("Erro ao remover variante do anexo:", err)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package media
+
+import (
+	"api/src/config"
+	"errors"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+var ErrInvalidCrop = errors.New("área de recorte fora dos limites da imagem")
+
+// Crop é a área quadrada escolhida pelo usuário, em pixels da imagem original já na
+// posição indicada pelo EXIF, como navegadores e celulares a exibem
+type Crop struct {
+	X    int
+	Y    int
+	Size int
+}
+
+// Avatar recorta a foto de perfil em um quadrado e gera os tamanhos configurados.
+// Sem recorte informado, usa o maior quadrado centralizado. Decode corrige a
+// orientação antes do recorte, então fotos de celular não ficam deitadas.
+func Avatar(raw []byte, outputType string, crop *Crop) ([]Encoded, error) {
+	img, err := Decode(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+
+	var area image.Rectangle
+	if crop == nil {
+		side := min(bounds.Dx(), bounds.Dy())
+		x := (bounds.Dx() - side) / 2
+		y := (bounds.Dy() - side) / 2
+		area = image.Rect(x, y, x+side, y+side).Add(bounds.Min)
+	} else {
+		area = image.Rect(crop.X, crop.Y, crop.X+crop.Size, crop.Y+crop.Size).Add(bounds.Min)
+		if crop.Size <= 0 || !area.In(bounds) {
+			return nil, ErrInvalidCrop
+		}
+	}
+
+	square := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Draw(square, square.Bounds(), img, area.Min, draw.Src)
+
+	return Variants(square, outputType, config.AvatarVariants)
+}
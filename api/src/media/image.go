@@ -0,0 +1,127 @@
+// Processamento de imagens enviadas: limpeza de metadados e geração de tamanhos
+package media
+
+import (
+	"api/src/config"
+	"bytes"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Limite de pixels aceitos, para que imagens gigantes não esgotem a memória ao decodificar
+const maxPixels = 50_000_000
+
+var ErrTooManyPixels = errors.New("imagem com dimensões grandes demais")
+
+// Encoded é uma imagem já redimensionada e codificada
+type Encoded struct {
+	Name   string
+	Data   []byte
+	Width  int
+	Height int
+}
+
+// OutputFormat retorna o formato em que uma imagem enviada será regravada:
+// formatos com transparência viram PNG e os demais viram JPEG
+func OutputFormat(contentType string) (outputType, extension string, ok bool) {
+	switch contentType {
+	case "image/png", "image/gif":
+		return "image/png", ".png", true
+	case "image/jpeg", "image/webp":
+		return "image/jpeg", ".jpg", true
+	}
+	return "", "", false
+}
+
+// Decode lê a imagem validando suas dimensões antes de decodificá-la por completo.
+// A orientação EXIF é aplicada aqui, já que a recodificação descarta a tag e fotos
+// de celular ficariam deitadas ou de cabeça para baixo.
+func Decode(raw []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	return orient(img, orientation(raw)), nil
+}
+
+// Variants gera os tamanhos configurados. As imagens são sempre recodificadas, o que
+// descarta EXIF, coordenadas de GPS e qualquer outro metadado do arquivo original.
+func Variants(img image.Image, outputType string, variants []config.ImageVariant) ([]Encoded, error) {
+	encoded := make([]Encoded, 0, len(variants))
+
+	for _, variant := range variants {
+		resized := Resize(img, variant.MaxSize)
+
+		data, err := Encode(resized, outputType)
+		if err != nil {
+			return nil, err
+		}
+
+		bounds := resized.Bounds()
+		encoded = append(encoded, Encoded{
+			Name:   variant.Name,
+			Data:   data,
+			Width:  bounds.Dx(),
+			Height: bounds.Dy(),
+		})
+	}
+
+	return encoded, nil
+}
+
+// Resize reduz a imagem para que o maior lado tenha no máximo maxSize pixels,
+// mantendo a proporção. Imagens menores não são ampliadas.
+func Resize(img image.Image, maxSize int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= maxSize && height <= maxSize {
+		// Copia mesmo assim para normalizar o formato de cor antes de codificar
+		dst := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(dst, dst.Bounds(), img, bounds.Min, draw.Src)
+		return dst
+	}
+
+	if width >= height {
+		height = max(1, height*maxSize/width)
+		width = maxSize
+	} else {
+		width = max(1, width*maxSize/height)
+		height = maxSize
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// Encode grava a imagem no formato de saída
+func Encode(img image.Image, outputType string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	switch outputType {
+	case "image/png":
+		err = png.Encode(&buf, img)
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: config.ImageJPEGQuality})
+	}
+
+	return buf.Bytes(), err
+}
@@ -0,0 +1,105 @@
+package media
+
+import (
+	"api/src/config"
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+)
+
+// A foto de teste tem 32x16 pixels, metade esquerda vermelha e metade direita azul,
+// gravada com orientação EXIF 6: na posição certa ela fica em pé, vermelha em cima
+func TestDecodeAppliesExifOrientation(t *testing.T) {
+	raw, err := os.ReadFile("testdata/orientation-6.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := orientation(raw); got != 6 {
+		t.Fatalf("orientação = %d, esperado 6", got)
+	}
+
+	img, err := Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := img.Bounds().Size(); got != image.Pt(16, 32) {
+		t.Fatalf("dimensões = %v, esperado 16x32", got)
+	}
+
+	if !isRed(img.At(8, 4)) {
+		t.Errorf("topo da imagem deveria ser vermelho, veio %v", img.At(8, 4))
+	}
+	if isRed(img.At(8, 28)) {
+		t.Errorf("base da imagem deveria ser azul, veio %v", img.At(8, 28))
+	}
+}
+
+// Para cada orientação, onde o pixel do canto superior esquerdo da imagem gravada
+// (2x1 pixels) vai parar depois de corrigida
+func TestOrient(t *testing.T) {
+	cases := []struct {
+		value  int
+		size   image.Point
+		corner image.Point
+	}{
+		{1, image.Pt(2, 1), image.Pt(0, 0)},
+		{2, image.Pt(2, 1), image.Pt(1, 0)},
+		{3, image.Pt(2, 1), image.Pt(1, 0)},
+		{4, image.Pt(2, 1), image.Pt(0, 0)},
+		{5, image.Pt(1, 2), image.Pt(0, 0)},
+		{6, image.Pt(1, 2), image.Pt(0, 0)},
+		{7, image.Pt(1, 2), image.Pt(0, 1)},
+		{8, image.Pt(1, 2), image.Pt(0, 1)},
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	src.Set(1, 0, color.RGBA{0, 0, 255, 255})
+
+	for _, c := range cases {
+		dst := orient(src, c.value)
+		if got := dst.Bounds().Size(); got != c.size {
+			t.Errorf("orientação %d: dimensões = %v, esperado %v", c.value, got, c.size)
+			continue
+		}
+		if !isRed(dst.At(c.corner.X, c.corner.Y)) {
+			t.Errorf("orientação %d: pixel vermelho deveria estar em %v", c.value, c.corner)
+		}
+	}
+}
+
+func isRed(c color.Color) bool {
+	r, _, b, _ := c.RGBA()
+	return r > 0xC000 && b < 0x4000
+}
+
+// O recorte do avatar é feito sobre a imagem já na posição certa: o quadrado de baixo
+// da foto de teste em pé (16x32) é todo azul, e nem caberia na foto deitada (32x16)
+func TestAvatarAppliesExifOrientation(t *testing.T) {
+	raw, err := os.ReadFile("testdata/orientation-6.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.AvatarVariants = []config.ImageVariant{{Name: "large", MaxSize: 16}}
+
+	encoded, err := Avatar(raw, "image/png", &Crop{X: 0, Y: 16, Size: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(encoded[0].Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bounds := img.Bounds()
+	if isRed(img.At(bounds.Dx()/2, bounds.Dy()/2)) {
+		t.Errorf("o avatar deveria ser azul, veio %v", img.At(bounds.Dx()/2, bounds.Dy()/2))
+	}
+}
@@ -0,0 +1,153 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+)
+
+// Tag EXIF com a orientação em que a câmera gravou a foto
+const orientationTag = 0x0112
+
+// orientation lê a orientação EXIF de uma imagem JPEG ou WebP: 1 quando a imagem já
+// está na posição certa, não tem EXIF ou o EXIF não pôde ser lido
+func orientation(raw []byte) int {
+	var tiff []byte
+	switch {
+	case bytes.HasPrefix(raw, []byte{0xFF, 0xD8}):
+		tiff = jpegExif(raw)
+	case len(raw) >= 12 && string(raw[0:4]) == "RIFF" && string(raw[8:12]) == "WEBP":
+		tiff = webpExif(raw)
+	}
+
+	value := tiffOrientation(tiff)
+	if value < 1 || value > 8 {
+		return 1
+	}
+	return value
+}
+
+// jpegExif procura o segmento APP1 "Exif" antes do início dos dados da imagem
+func jpegExif(raw []byte) []byte {
+	pos := 2
+	for pos+4 <= len(raw) {
+		if raw[pos] != 0xFF {
+			return nil
+		}
+		marker := raw[pos+1]
+		// Início dos dados (SOS) ou fim da imagem: não há mais metadados
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(raw[pos+2:]))
+		if length < 2 || pos+2+length > len(raw) {
+			return nil
+		}
+
+		segment := raw[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+
+		pos += 2 + length
+	}
+	return nil
+}
+
+// webpExif procura o bloco "EXIF" do contêiner RIFF
+func webpExif(raw []byte) []byte {
+	pos := 12
+	for pos+8 <= len(raw) {
+		size := int(binary.LittleEndian.Uint32(raw[pos+4:]))
+		if size < 0 || pos+8+size > len(raw) {
+			return nil
+		}
+
+		if string(raw[pos:pos+4]) == "EXIF" {
+			// Alguns gravadores mantêm o prefixo do JPEG também no WebP
+			return bytes.TrimPrefix(raw[pos+8:pos+8+size], []byte("Exif\x00\x00"))
+		}
+
+		// Blocos de tamanho ímpar são completados com um byte
+		pos += 8 + size + size%2
+	}
+	return nil
+}
+
+// tiffOrientation lê a tag de orientação no primeiro diretório (IFD0) do TIFF do EXIF
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			// Valor SHORT gravado no início do campo de valor da entrada
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// orient gira ou espelha a imagem conforme a orientação EXIF, para que ela fique na
+// posição em que foi fotografada; a recodificação descarta a tag depois
+func orient(img image.Image, value int) image.Image {
+	if value <= 1 || value > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Da orientação 5 em diante a imagem é girada 90°, trocando largura e altura
+	dstWidth, dstHeight := width, height
+	if value >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var sx, sy int
+			switch value {
+			case 2: // espelhada na horizontal
+				sx, sy = width-1-x, y
+			case 3: // girada 180°
+				sx, sy = width-1-x, height-1-y
+			case 4: // espelhada na vertical
+				sx, sy = x, height-1-y
+			case 5: // espelhada na diagonal principal
+				sx, sy = y, x
+			case 6: // precisa girar 90° no sentido horário
+				sx, sy = y, height-1-x
+			case 7: // espelhada na diagonal secundária
+				sx, sy = width-1-y, height-1-x
+			case 8: // precisa girar 90° no sentido anti-horário
+				sx, sy = width-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,65 @@
+package media
+
+import (
+	"errors"
+	"log"
+)
+
+// Erro retornado quando a fila de processamento está cheia
+var ErrQueueFull = errors.New("fila de processamento de imagens cheia")
+
+var queue chan func()
+
+// Start inicia um número fixo de workers para processar imagens fora das
+// goroutines das requisições
+func Start(workers, size int) {
+	queue = make(chan func(), size)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for task := range queue {
+				run(task)
+			}
+		}()
+	}
+
+	log.Printf("🖼️  Processamento de imagens iniciado (%d workers).\n", workers)
+}
+
+// Submit enfileira uma tarefa sem bloquear; com a fila cheia retorna ErrQueueFull
+func Submit(task func()) error {
+	select {
+	case queue <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// run executa a tarefa sem deixar um panic derrubar o worker
+func run(task func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Println("Erro inesperado no processamento de imagem:", recovered)
+		}
+	}()
+
+	task()
+}
+
+// Do executa a tarefa no pool e espera sua conclusão, para processamentos cujo
+// resultado a própria requisição precisa devolver
+func Do(task func()) error {
+	done := make(chan struct{})
+
+	err := Submit(func() {
+		defer close(done)
+		task()
+	})
+	if err != nil {
+		return err
+	}
+
+	<-done
+	return nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// Representa um arquivo anexado a um post ou comentário
+type Attachment struct {
+	ID          uint64              `json:"id"`
+	OwnerID     uint64              `json:"owner_id"`
+	PostID      *uint64             `json:"post_id,omitempty"`
+	CommentID   *uint64             `json:"comment_id,omitempty"`
+	StorageKey  string              `json:"-"`
+	Filename    string              `json:"filename"`
+	ContentType string              `json:"content_type"`
+	Size        int64               `json:"size"`
+	URL         string              `json:"url"`
+	Status      string              `json:"status"`
+	AltText     *string             `json:"alt_text"`
+	Width       *int                `json:"width,omitempty"`
+	Height      *int                `json:"height,omitempty"`
+	Variants    []AttachmentVariant `json:"variants,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// StorageKeys lista todas as chaves do anexo no armazenamento, incluindo as variantes
+func (a Attachment) StorageKeys() []string {
+	keys := []string{a.StorageKey}
+	for _, variant := range a.Variants {
+		if variant.StorageKey != a.StorageKey {
+			keys = append(keys, variant.StorageKey)
+		}
+	}
+	return keys
+}
+
+// Representa um tamanho gerado de uma imagem anexada
+type AttachmentVariant struct {
+	Name       string `json:"name"`
+	StorageKey string `json:"-"`
+	URL        string `json:"url"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Size       int64  `json:"size"`
+}
+
+// Estados de um anexo; imagens ficam em processamento até os tamanhos serem gerados
+const (
+	AttachmentProcessing = "processing"
+	AttachmentReady      = "ready"
+	AttachmentFailed     = "failed"
+)
+
+// Tipos de arquivo aceitos nos anexos, identificados pelo conteúdo e não pela extensão
+var AllowedAttachmentTypes = map[string]string{
+	"image/jpeg":                ".jpg",
+	"image/png":                 ".png",
+	"image/gif":                 ".gif",
+	"image/webp":                ".webp",
+	"application/pdf":           ".pdf",
+	"application/zip":           ".zip",
+	"text/plain; charset=utf-8": ".txt",
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// Conquistas automáticas, concedidas pelo agendador quando o usuário atinge a regra
+// (ver repository/badges.go), e manuais, concedidas por administradores
+const (
+	BadgeFirstPost = "first_post"
+	BadgePopular   = "popular"
+	BadgeMentor    = "mentor"
+	BadgeVeteran   = "veteran"
+	BadgeStaff     = "staff"
+	BadgeVerified  = "verified"
+)
+
+// Badge é uma conquista do catálogo
+type Badge struct {
+	Slug        string `json:"slug"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Manual      bool   `json:"manual"`
+	Holders     uint64 `json:"holders"`
+}
+
+// Catálogo de conquistas, na ordem em que são exibidas
+var Badges = []Badge{
+	{Slug: BadgeFirstPost, Name: "Primeiro post", Description: "Publicou o primeiro post"},
+	{Slug: BadgePopular, Name: "Popular", Description: "Recebeu 100 curtidas nos seus posts"},
+	{Slug: BadgeMentor, Name: "Mentor", Description: "Teve 10 respostas aceitas"},
+	{Slug: BadgeVeteran, Name: "Veterano", Description: "Membro há pelo menos um ano"},
+	{Slug: BadgeStaff, Name: "Staff", Description: "Faz parte da equipe", Manual: true},
+	{Slug: BadgeVerified, Name: "Verificado", Description: "Identidade verificada pela equipe", Manual: true},
+}
+
+// FindBadge busca uma conquista do catálogo pelo slug
+func FindBadge(slug string) (Badge, bool) {
+	for _, badge := range Badges {
+		if badge.Slug == slug {
+			return badge, true
+		}
+	}
+	return Badge{}, false
+}
+
+// UserBadge é uma conquista exibida no perfil
+type UserBadge struct {
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	AwardedAt time.Time `json:"awardedAt"`
+}
+
+// BadgeHolder é um usuário que tem a conquista, usado para listar quem a recebeu
+type BadgeHolder struct {
+	User      PublicProfile `json:"user"`
+	AwardedAt time.Time     `json:"awardedAt"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const maxCollectionNameLength = 100
+
+// Collection é uma lista nomeada de posts salvos
+type Collection struct {
+	ID        uint64    `json:"id"`
+	UserID    uint64    `json:"userId"`
+	Name      string    `json:"name"`
+	Public    bool      `json:"public"`
+	PostCount uint64    `json:"postCount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CollectionInput cria ou atualiza uma coleção. Na atualização, campos nulos mantêm
+// o valor atual.
+type CollectionInput struct {
+	Name   *string `json:"name"`
+	Public *bool   `json:"public"`
+}
+
+// Prepare valida e formata os dados da coleção; na criação o nome é obrigatório
+func (c *CollectionInput) Prepare(create bool) error {
+	if c.Name != nil {
+		name := strings.TrimSpace(*c.Name)
+		c.Name = &name
+	}
+
+	if create && c.Name == nil {
+		return errors.New("o nome da coleção é obrigatório")
+	}
+	if !create && c.Name == nil && c.Public == nil {
+		return errors.New("informe name ou public")
+	}
+	if c.Name != nil && *c.Name == "" {
+		return errors.New("o nome da coleção é obrigatório")
+	}
+	if c.Name != nil && len([]rune(*c.Name)) > maxCollectionNameLength {
+		return errors.New("o nome da coleção deve ter no máximo 100 caracteres")
+	}
+
+	return nil
+}
+
+// SavedPost é um post nos favoritos ou em uma coleção. Posts excluídos ou que quem
+// consulta não pode mais ver ficam com Available falso e sem o conteúdo.
+type SavedPost struct {
+	PostID    uint64    `json:"postId"`
+	Available bool      `json:"available"`
+	Post      *Post     `json:"post,omitempty"`
+	SavedAt   time.Time `json:"savedAt"`
+}
+
+// PostOrder é a nova ordem dos posts salvos; os que ficarem de fora vão para o
+// final, na ordem em que estavam
+type PostOrder struct {
+	PostIDs []uint64 `json:"postIds"`
+}
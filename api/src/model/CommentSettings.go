@@ -0,0 +1,27 @@
+package model
+
+import "errors"
+
+// Quem pode comentar em um post
+const (
+	CommentPolicyEveryone  = "everyone"  // qualquer usuário que veja o post
+	CommentPolicyFollowers = "followers" // apenas seguidores do autor
+)
+
+// CommentSettings são as regras de comentário definidas pelo autor do post.
+// Na atualização, campos nulos mantêm o valor atual.
+type CommentSettings struct {
+	Locked *bool   `json:"locked"`
+	Policy *string `json:"policy"`
+}
+
+// Validate confere a política de comentários informada
+func (s CommentSettings) Validate() error {
+	if s.Locked == nil && s.Policy == nil {
+		return errors.New("informe locked ou policy")
+	}
+	if s.Policy != nil && *s.Policy != CommentPolicyEveryone && *s.Policy != CommentPolicyFollowers {
+		return errors.New("política de comentários inválida")
+	}
+	return nil
+}
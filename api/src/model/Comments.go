@@ -1,22 +1,83 @@
 package model
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
 type Comment struct {
-	ID        uint64 `json:"id"`
-	PostID    uint64 `json:"postId"`
-	AuthorID  uint64 `json:"authorId"`
-	Content   string `json:"content"`
-	CreatedAt string `json:"createdAt"`
+	ID          uint64       `json:"id"`
+	PostID      uint64       `json:"postId"`
+	ParentID    *uint64      `json:"parentId,omitempty"`
+	Depth       int          `json:"depth"`
+	AuthorID    uint64       `json:"authorId"`
+	Content     string       `json:"content"`
+	ContentHTML string       `json:"content_html,omitempty"`
+	CreatedAt   string       `json:"createdAt"`
+	EditedAt    *time.Time   `json:"editedAt,omitempty"`
+	DeletedAt   *time.Time   `json:"deletedAt,omitempty"`
+	Hidden      bool         `json:"hidden"`
+	Pinned      bool         `json:"pinned"`
+	Score       int          `json:"score"`
+	Accepted    bool         `json:"accepted"`
+	MyVote      int          `json:"myVote"`
+	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
 type CommentAuthor struct {
-	ID   uint64 `json:"id"`
-	Name string `json:"name"`
-	Nick string `json:"nick"`
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Nick      string `json:"nick"`
+	AvatarURL string `json:"avatarUrl"`
 }
 
+// Texto exibido no lugar de um comentário removido que ainda tem respostas
+const RemovedCommentPlaceholder = "[removed]"
+
 type CommentResponse struct {
-	ID        uint64        `json:"id"`
-	Content   string        `json:"content"`
-	CreatedAt string        `json:"createdAt"`
-	Author    CommentAuthor `json:"author"`
+	ID          uint64         `json:"id"`
+	ParentID    *uint64        `json:"parentId"`
+	Depth       int            `json:"depth"`
+	Content     string         `json:"content"`
+	ContentHTML string         `json:"content_html"`
+	CreatedAt   string         `json:"createdAt"`
+	EditedAt    *time.Time     `json:"editedAt"`
+	Author      *CommentAuthor `json:"author"`
+	ReplyCount  uint64         `json:"replyCount"`
+	Removed     bool           `json:"removed"`
+	Hidden      bool           `json:"hidden"`
+	Pinned      bool           `json:"pinned"`
+	Score       int            `json:"score"`
+	Accepted    bool           `json:"accepted"`
+	MyVote      int            `json:"myVote"`
+	Attachments []Attachment   `json:"attachments"`
+	ReactionSummary
+}
+
+// CommentRevision é uma versão anterior (ou a atual) do texto de um comentário
+type CommentRevision struct {
+	ID             uint64    `json:"id"`
+	CommentID      uint64    `json:"commentId"`
+	EditorID       uint64    `json:"editorId"`
+	EditorNickname string    `json:"editorNickname"`
+	Content        string    `json:"content"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// Prepare valida e formata o comentário, na criação e na edição
+func (c *Comment) Prepare() error {
+	c.format()
+	return c.validate()
+}
+
+func (c *Comment) validate() error {
+	if c.Content == "" {
+		return errors.New("o comentário não pode ser vazio")
+	}
+	return nil
+}
+
+func (c *Comment) format() {
+	c.Content = strings.TrimSpace(c.Content)
 }
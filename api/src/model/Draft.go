@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// Representa um salvamento automático de rascunho.
+// Apenas os campos enviados são alterados.
+type DraftPatch struct {
+	Title      *string `json:"title"`
+	Content    *string `json:"content"`
+	Visibility *string `json:"visibility"`
+	Type       *string `json:"type"`
+	// Post citado; verificado com CheckShareable ao salvar e de novo ao publicar
+	QuotedPostID *uint64 `json:"quoted_post_id"`
+}
+
+// Prepare valida e formata os campos enviados no autosave
+func (d *DraftPatch) Prepare() error {
+	if d.Title != nil {
+		title := strings.TrimSpace(*d.Title)
+		if len([]rune(title)) > 100 {
+			return errors.New("o titulo deve ter no máximo 100 caracteres")
+		}
+		d.Title = &title
+	}
+
+	if d.Content != nil {
+		content := strings.TrimSpace(*d.Content)
+		d.Content = &content
+	}
+
+	if d.Visibility != nil {
+		visibility := strings.ToLower(strings.TrimSpace(*d.Visibility))
+		if !ValidVisibility(visibility) {
+			return errors.New("visibilidade inválida")
+		}
+		d.Visibility = &visibility
+	}
+
+	if d.Type != nil {
+		postType := strings.ToLower(strings.TrimSpace(*d.Type))
+		if !ValidPostType(postType) {
+			return errors.New("tipo de post inválido")
+		}
+		d.Type = &postType
+	}
+
+	return nil
+}
+
+// Apply aplica os campos enviados ao post e valida o resultado como um post completo
+func (d DraftPatch) Apply(post *Post) error {
+	if d.Title != nil {
+		post.Title = *d.Title
+	}
+	if d.Content != nil {
+		post.Content = *d.Content
+	}
+	if d.Visibility != nil {
+		post.Visibility = *d.Visibility
+	}
+	if d.Type != nil {
+		post.Type = *d.Type
+	}
+	if d.QuotedPostID != nil {
+		post.QuotedPostID = d.QuotedPostID
+	}
+
+	return post.Prepare()
+}
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+// FollowRequest é um pedido para seguir uma conta privada, ainda não respondido
+type FollowRequest struct {
+	User      PublicProfile `json:"user"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
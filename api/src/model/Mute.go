@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const maxMutedPhraseLength = 100
+
+// MutedUser é uma conta silenciada: seus posts e comentários somem dos feeds de quem silenciou
+type MutedUser struct {
+	User      PublicProfile `json:"user"`
+	ExpiresAt *time.Time    `json:"expiresAt"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
+
+// MuteOptions é o corpo opcional ao silenciar uma conta; sem expiresAt o silêncio é permanente
+type MuteOptions struct {
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// MutedKeyword é uma palavra, frase ou hashtag silenciada
+type MutedKeyword struct {
+	ID            uint64     `json:"id"`
+	Phrase        string     `json:"phrase"`
+	WholeWord     bool       `json:"wholeWord"`
+	CaseSensitive bool       `json:"caseSensitive"`
+	ExpiresAt     *time.Time `json:"expiresAt"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+func (m *MuteOptions) Validate() error {
+	return validateExpiry(m.ExpiresAt)
+}
+
+func (k *MutedKeyword) Prepare() error {
+	k.Phrase = strings.TrimSpace(k.Phrase)
+
+	if k.Phrase == "" {
+		return errors.New("a palavra ou frase é obrigatória")
+	}
+	if len([]rune(k.Phrase)) > maxMutedPhraseLength {
+		return errors.New("a palavra ou frase deve ter no máximo 100 caracteres")
+	}
+
+	return validateExpiry(k.ExpiresAt)
+}
+
+// Pattern monta a expressão regular usada pelo banco (REGEXP_LIKE) para encontrar a
+// frase. No modo palavra inteira, a frase não pode estar colada a letras, números ou _,
+// o que também funciona para hashtags ("#go" não silencia "#golang").
+func (k MutedKeyword) Pattern() string {
+	pattern := regexp.QuoteMeta(k.Phrase)
+	if k.WholeWord {
+		pattern = `(^|[^\p{L}\p{N}_])` + pattern + `([^\p{L}\p{N}_]|$)`
+	}
+	return pattern
+}
+
+func validateExpiry(expiresAt *time.Time) error {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errors.New("a data de expiração deve estar no futuro")
+	}
+	return nil
+}
@@ -7,12 +7,65 @@ import (
 )
 
 type Post struct {
-	ID             uint64    `gorm:"primaryKey" json:"id,omitempty"`
-	Title          string    `json:"title,omitempty"`
-	Content        string    `json:"content,omitempty"`
-	AuthorID       uint64    `json:"author_id,omitempty"`
-	AuthorNickname string    `json:"author_nickname,omitempty"`
-	CreatedAt      time.Time `json:"created_at,omitempty"`
+	ID             uint64       `gorm:"primaryKey" json:"id,omitempty"`
+	Title          string       `json:"title,omitempty"`
+	Content        string       `json:"content,omitempty"`
+	ContentHTML    string       `json:"content_html,omitempty"`
+	AuthorID       uint64       `json:"author_id,omitempty"`
+	AuthorNickname string       `json:"author_nickname,omitempty"`
+	Visibility     string       `json:"visibility,omitempty"`
+	Type           string       `json:"type,omitempty"`
+	Answered       bool         `json:"answered,omitempty"`
+	Status         string       `json:"status,omitempty"`
+	PublishAt      *time.Time   `json:"publish_at,omitempty"`
+	EditedAt       *time.Time   `json:"edited_at,omitempty"`
+	RevisionCount  uint64       `json:"revision_count"`
+	DeletedAt      *time.Time   `json:"deleted_at,omitempty"`
+	Attachments    []Attachment `json:"attachments,omitempty"`
+	QuotedPostID   *uint64      `json:"quoted_post_id,omitempty"`
+	QuotedPost     *QuotedPost  `json:"quoted_post,omitempty"`
+	CreatedAt      time.Time    `json:"created_at,omitempty"`
+}
+
+// Níveis de visibilidade de um post
+const (
+	VisibilityPublic    = "public"    // qualquer usuário autenticado
+	VisibilityFollowers = "followers" // apenas seguidores do autor
+	VisibilityUnlisted  = "unlisted"  // acessível pelo link, mas fora dos feeds
+	VisibilityPrivate   = "private"   // apenas o autor
+)
+
+// Tipos de post. Perguntas têm respostas votadas e uma resposta aceita.
+const (
+	PostTypePost     = "post"
+	PostTypeQuestion = "question"
+)
+
+// Estados de publicação de um post
+const (
+	PostStatusDraft     = "draft"
+	PostStatusScheduled = "scheduled"
+	PostStatusPublished = "published"
+)
+
+// ValidVisibility indica se o valor é um nível de visibilidade conhecido
+func ValidVisibility(visibility string) bool {
+	switch visibility {
+	case VisibilityPublic, VisibilityFollowers, VisibilityUnlisted, VisibilityPrivate:
+		return true
+	}
+	return false
+}
+
+// FeedFilter restringe o feed a um tipo de post ou às perguntas ainda sem resposta aceita
+type FeedFilter struct {
+	Type       string
+	Unanswered bool
+}
+
+// ValidPostType indica se o valor é um tipo de post conhecido
+func ValidPostType(postType string) bool {
+	return postType == PostTypePost || postType == PostTypeQuestion
 }
 
 // Prepare valida e formata os dados do post
@@ -35,9 +88,23 @@ func (p *Post) validate() error {
 	if p.AuthorID == 0 {
 		return errors.New("o ID do autor é obrigatório")
 	}
+	if p.Visibility != "" && !ValidVisibility(strings.ToLower(strings.TrimSpace(p.Visibility))) {
+		return errors.New("visibilidade inválida")
+	}
+	if p.Type != "" && !ValidPostType(strings.ToLower(strings.TrimSpace(p.Type))) {
+		return errors.New("tipo de post inválido")
+	}
 	return nil
 }
 func (p *Post) format() {
 	p.Title = strings.TrimSpace(p.Title)
 	p.Content = strings.TrimSpace(p.Content)
+	p.Visibility = strings.ToLower(strings.TrimSpace(p.Visibility))
+	if p.Visibility == "" {
+		p.Visibility = VisibilityPublic
+	}
+	p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	if p.Type == "" {
+		p.Type = PostTypePost
+	}
 }
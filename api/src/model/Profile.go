@@ -0,0 +1,159 @@
+package model
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxBioLength      = 1000
+	maxTechStackTags  = 20
+	maxTechTagLength  = 30
+	maxLocationLength = 100
+	maxPronounsLength = 50
+	maxWebsiteLength  = 255
+)
+
+var (
+	githubHandle = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,37}[A-Za-z0-9])?$`)
+	gitlabHandle = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,254}$`)
+	techTag      = regexp.MustCompile(`^[a-z0-9][a-z0-9+#. -]*$`)
+)
+
+// Profile reúne os campos editáveis do perfil. Na atualização, campos não enviados
+// (nil) mantêm o valor atual e uma string vazia apaga o campo.
+type Profile struct {
+	Bio       *string  `json:"bio,omitempty"`
+	BioHTML   string   `json:"bioHtml,omitempty"`
+	Website   *string  `json:"website,omitempty"`
+	Location  *string  `json:"location,omitempty"`
+	Pronouns  *string  `json:"pronouns,omitempty"`
+	GitHub    *string  `json:"github,omitempty"`
+	GitLab    *string  `json:"gitlab,omitempty"`
+	TechStack []string `json:"techStack,omitempty"`
+	BannerURL *string  `json:"bannerUrl,omitempty"`
+
+	// Em contas privadas, novos seguidores precisam ser aprovados
+	Private *bool `json:"private,omitempty"`
+}
+
+// UserStats são os contadores exibidos no perfil
+type UserStats struct {
+	Followers     uint64 `json:"followers"`
+	Following     uint64 `json:"following"`
+	Posts         uint64 `json:"posts"`
+	LikesReceived uint64 `json:"likesReceived"`
+	Reputation    int64  `json:"reputation"`
+}
+
+// Relationship descreve o follow entre quem consulta e o dono do perfil
+type Relationship struct {
+	Following  bool `json:"following"`
+	FollowedBy bool `json:"followedBy"`
+	Mutual     bool `json:"mutual"`
+	Requested  bool `json:"requested"`
+	Muted      bool `json:"muted"`
+}
+
+// PublicProfile é o que outros usuários enxergam: nunca inclui o email
+type PublicProfile struct {
+	ID           uint64        `json:"id"`
+	Name         string        `json:"name"`
+	Nick         string        `json:"nick"`
+	AvatarURL    string        `json:"avatarUrl,omitempty"`
+	CreatedAt    string        `json:"createdAt"`
+	Stats        UserStats     `json:"stats"`
+	Badges       []UserBadge   `json:"badges,omitempty"`
+	Relationship *Relationship `json:"relationship,omitempty"`
+	Profile
+}
+
+func (p *Profile) validate() error {
+	if p.Bio != nil && len([]rune(*p.Bio)) > maxBioLength {
+		return errors.New("a bio deve ter no máximo 1000 caracteres")
+	}
+
+	if website := trimmed(p.Website); website != "" {
+		if len(website) > maxWebsiteLength {
+			return errors.New("o site deve ter no máximo 255 caracteres")
+		}
+		parsed, err := url.Parse(website)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.New("o site deve ser um endereço http ou https válido")
+		}
+	}
+
+	if p.Location != nil && len([]rune(*p.Location)) > maxLocationLength {
+		return errors.New("a localização deve ter no máximo 100 caracteres")
+	}
+
+	if p.Pronouns != nil && len([]rune(*p.Pronouns)) > maxPronounsLength {
+		return errors.New("os pronomes devem ter no máximo 50 caracteres")
+	}
+
+	if handle := strings.TrimPrefix(trimmed(p.GitHub), "@"); handle != "" {
+		if !githubHandle.MatchString(handle) || strings.Contains(handle, "--") {
+			return errors.New("usuário do GitHub inválido")
+		}
+	}
+
+	if handle := strings.TrimPrefix(trimmed(p.GitLab), "@"); handle != "" {
+		if !gitlabHandle.MatchString(handle) {
+			return errors.New("usuário do GitLab inválido")
+		}
+	}
+
+	if len(p.TechStack) > maxTechStackTags {
+		return errors.New("a stack deve ter no máximo 20 tecnologias")
+	}
+	for _, tag := range p.TechStack {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len([]rune(tag)) > maxTechTagLength || !techTag.MatchString(tag) {
+			return errors.New("tecnologia inválida na stack: " + tag)
+		}
+	}
+
+	return nil
+}
+
+func (p *Profile) format() {
+	for _, field := range []*string{p.Bio, p.Website, p.Location, p.Pronouns} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	for _, handle := range []*string{p.GitHub, p.GitLab} {
+		if handle != nil {
+			*handle = strings.TrimPrefix(strings.TrimSpace(*handle), "@")
+		}
+	}
+
+	// Remove vazias e repetidas, mantendo a ordem escolhida pelo usuário
+	if p.TechStack != nil {
+		seen := map[string]bool{}
+		tags := []string{}
+		for _, tag := range p.TechStack {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+		p.TechStack = tags
+	}
+}
+
+// trimmed lê um campo opcional já sem espaços nas pontas
+func trimmed(field *string) string {
+	if field == nil {
+		return ""
+	}
+	return strings.TrimSpace(*field)
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Reação registrada pelo like dos posts, mantido por compatibilidade
+const LikeReaction = "👍"
+
+// ReactionCount é o total de reações com um mesmo emoji
+type ReactionCount struct {
+	Emoji string `json:"emoji"`
+	Count uint64 `json:"count"`
+}
+
+// ReactionSummary agrega as reações de um comentário e indica as de quem consulta
+type ReactionSummary struct {
+	Reactions   []ReactionCount `json:"reactions"`
+	MyReactions []string        `json:"myReactions"`
+}
+
+// PostReactionSummary agrega as reações de um post; cada usuário tem no máximo uma
+type PostReactionSummary struct {
+	Reactions  []ReactionCount `json:"reactions"`
+	MyReaction *string         `json:"myReaction"`
+}
+
+// Reaction é a reação de um usuário, usada para listar quem reagiu
+type Reaction struct {
+	User      PublicProfile `json:"user"`
+	Emoji     string        `json:"emoji"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
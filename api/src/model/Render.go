@@ -0,0 +1,6 @@
+package model
+
+// Representa o conteúdo enviado para pré-visualização
+type RenderPreview struct {
+	Content string `json:"content"`
+}
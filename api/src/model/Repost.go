@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// QuotedPost é o post citado, embutido na citação. Fica ausente quando quem consulta
+// não pode ver o post original.
+type QuotedPost struct {
+	ID             uint64    `json:"id"`
+	Title          string    `json:"title"`
+	ContentHTML    string    `json:"content_html"`
+	AuthorID       uint64    `json:"author_id"`
+	AuthorNickname string    `json:"author_nickname"`
+	AuthorPhotoURL string    `json:"author_photo_url"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RepostedBy é a atribuição de um post que aparece no feed por ter sido repostado
+type RepostedBy struct {
+	UserID     uint64    `json:"user_id"`
+	Nickname   string    `json:"nickname"`
+	RepostedAt time.Time `json:"reposted_at"`
+}
+
+// Repost é o repost de um usuário, usado para listar quem repostou
+type Repost struct {
+	User      PublicProfile `json:"user"`
+	CreatedAt time.Time     `json:"createdAt"`
+}
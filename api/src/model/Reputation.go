@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Eventos que alteram a reputação de quem os recebe. Os pontos de cada um ficam em
+// config.ReputationRules.
+const (
+	ReputationPostLiked       = "post_liked"       // reação recebida em um post
+	ReputationAnswerUpvoted   = "answer_upvoted"   // voto positivo em uma resposta
+	ReputationAnswerDownvoted = "answer_downvoted" // voto negativo em uma resposta
+	ReputationAnswerAccepted  = "answer_accepted"  // resposta aceita por quem perguntou
+	ReputationFollowerGained  = "follower_gained"  // novo seguidor
+	ReputationCommentHidden   = "comment_hidden"   // comentário ocultado pelo autor do post
+	ReputationCommentRemoved  = "comment_removed"  // comentário excluído pelo autor do post
+)
+
+// ReputationEvent é uma entrada do histórico de reputação de um usuário
+type ReputationEvent struct {
+	ID        uint64    `json:"id"`
+	Event     string    `json:"event"`
+	Points    int       `json:"points"`
+	PostID    *uint64   `json:"postId,omitempty"`
+	CommentID *uint64   `json:"commentId,omitempty"`
+	ActorID   *uint64   `json:"actorId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Representa uma versão salva de um post
+type Revision struct {
+	ID             uint64    `json:"id"`
+	PostID         uint64    `json:"post_id"`
+	EditorID       uint64    `json:"editor_id"`
+	EditorNickname string    `json:"editor_nickname"`
+	Title          string    `json:"title"`
+	Content        string    `json:"content,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Representa a diferença entre duas revisões de um post
+type RevisionDiff struct {
+	From    uint64     `json:"from"`
+	To      uint64     `json:"to"`
+	Title   []DiffLine `json:"title"`
+	Content []DiffLine `json:"content"`
+}
+
+// Representa uma linha do diff
+type DiffLine struct {
+	Op   string `json:"op"` // equal, insert ou delete
+	Text string `json:"text"`
+}
@@ -0,0 +1,19 @@
+package model
+
+// Papéis dos usuários. Moderadores podem ver o histórico de edições e agir sobre
+// conteúdo de terceiros; administradores têm todas as permissões de moderador.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// IsAdmin indica se o papel tem permissões de administração
+func IsAdmin(role string) bool {
+	return role == RoleAdmin
+}
+
+// IsModerator indica se o papel tem permissões de moderação
+func IsModerator(role string) bool {
+	return role == RoleModerator || role == RoleAdmin
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Representa o agendamento de publicação de um post
+type Schedule struct {
+	PublishAt time.Time `json:"publish_at"`
+}
+
+// Validate garante que a data de publicação foi enviada e está no futuro
+func (s Schedule) Validate() error {
+	if s.PublishAt.IsZero() {
+		return errors.New("a data de publicação é obrigatória")
+	}
+	if !s.PublishAt.After(time.Now()) {
+		return errors.New("a data de publicação deve estar no futuro")
+	}
+	return nil
+}
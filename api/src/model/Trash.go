@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Representa a lixeira de um usuário
+type Trash struct {
+	Posts    []Post    `json:"posts"`
+	Comments []Comment `json:"comments"`
+	// Itens excluídos antes desta data já foram (ou serão) removidos definitivamente
+	PurgeBefore time.Time `json:"purge_before"`
+}
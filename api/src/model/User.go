@@ -10,12 +10,31 @@ import (
 )
 
 type User struct {
-	ID        uint64 `json:"id"`
-	Name      string `json:"name"`
-	Email     string `json:"email"`
-	Nick      string `json:"nick"`
-	Password  string `json:"password,omitempty"`
-	CreatedAt string `json:"createdAt"`
+	ID        uint64      `json:"id"`
+	Name      string      `json:"name"`
+	Email     string      `json:"email,omitempty"`
+	Nick      string      `json:"nick"`
+	Password  string      `json:"password,omitempty"`
+	AvatarURL string      `json:"avatarUrl,omitempty"`
+	Role      string      `json:"role,omitempty"`
+	CreatedAt string      `json:"createdAt"`
+	Stats     UserStats   `json:"stats"`
+	Badges    []UserBadge `json:"badges,omitempty"`
+	Profile
+}
+
+// Public remove os dados privados do usuário, como o email
+func (u User) Public() PublicProfile {
+	return PublicProfile{
+		ID:        u.ID,
+		Name:      u.Name,
+		Nick:      u.Nick,
+		AvatarURL: u.AvatarURL,
+		CreatedAt: u.CreatedAt,
+		Stats:     u.Stats,
+		Badges:    u.Badges,
+		Profile:   u.Profile,
+	}
 }
 
 func (u *User) Prepare(stage string) error {
@@ -74,10 +93,11 @@ func (u *User) validate(stage string) error {
 		return errors.New("o nick é obrigatório")
 	}
 
-	return nil
+	return u.Profile.validate()
 }
 func (u *User) format() {
 	u.Name = strings.TrimSpace(u.Name)
 	u.Nick = strings.TrimSpace(u.Nick)
 	u.Email = strings.ToLower(strings.TrimSpace(u.Email))
+	u.Profile.format()
 }
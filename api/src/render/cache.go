@@ -0,0 +1,91 @@
+package render
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"strconv"
+	"sync"
+)
+
+// Quantidade máxima de conteúdos renderizados mantidos em memória
+const cacheSize = 5000
+
+type cacheEntry struct {
+	key  string
+	sum  [sha256.Size]byte
+	html string
+}
+
+// cache LRU do HTML renderizado. Cada entrada guarda o hash do texto de origem,
+// então um conteúdo alterado por outra instância da API nunca é servido desatualizado.
+var cache = struct {
+	sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}{
+	order:   list.New(),
+	entries: map[string]*list.Element{},
+}
+
+// Post retorna o HTML do conteúdo de um post, usando o cache quando possível
+func Post(postID uint64, source string) string {
+	return cached("post:"+strconv.FormatUint(postID, 10), source)
+}
+
+// Comment retorna o HTML do conteúdo de um comentário, usando o cache quando possível
+func Comment(commentID uint64, source string) string {
+	return cached("comment:"+strconv.FormatUint(commentID, 10), source)
+}
+
+// InvalidatePost descarta o HTML em cache de um post
+func InvalidatePost(postID uint64) {
+	invalidate("post:" + strconv.FormatUint(postID, 10))
+}
+
+// InvalidateComment descarta o HTML em cache de um comentário
+func InvalidateComment(commentID uint64) {
+	invalidate("comment:" + strconv.FormatUint(commentID, 10))
+}
+
+func cached(key, source string) string {
+	sum := sha256.Sum256([]byte(source))
+
+	cache.Lock()
+	if element, ok := cache.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		if entry.sum == sum {
+			cache.order.MoveToFront(element)
+			cache.Unlock()
+			return entry.html
+		}
+	}
+	cache.Unlock()
+
+	html := Markdown(source)
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		cache.order.Remove(element)
+	}
+	cache.entries[key] = cache.order.PushFront(&cacheEntry{key: key, sum: sum, html: html})
+
+	if cache.order.Len() > cacheSize {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*cacheEntry).key)
+	}
+
+	return html
+}
+
+func invalidate(key string) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if element, ok := cache.entries[key]; ok {
+		cache.order.Remove(element)
+		delete(cache.entries, key)
+	}
+}
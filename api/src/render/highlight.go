@@ -0,0 +1,44 @@
+package render
+
+import (
+	"api/src/config"
+	"bytes"
+	"log"
+	"regexp"
+	"sync"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// O tema escuro do frontend é ativado pela classe "dark" no elemento <html>
+const darkThemeSelector = "html.dark"
+
+var (
+	stylesheet     string
+	stylesheetOnce sync.Once
+
+	// Início de cada regra gerada pelo chroma: "/* Comentário */ .seletor"
+	cssRule = regexp.MustCompile(`(?m)^(/\*[^*]*\*/ )?\.`)
+)
+
+// Stylesheet retorna o CSS das classes do destaque de sintaxe: o estilo claro por
+// padrão e o estilo escuro quando o tema escuro está ativo.
+func Stylesheet() string {
+	stylesheetOnce.Do(func() {
+		formatter := chromahtml.New(chromahtml.WithClasses(true), chromahtml.ClassPrefix(classPrefix))
+
+		var light, dark bytes.Buffer
+		if err := formatter.WriteCSS(&light, styles.Get(config.HighlightLightStyle)); err != nil {
+			log.Println("Erro ao gerar CSS do tema claro:", err)
+		}
+		if err := formatter.WriteCSS(&dark, styles.Get(config.HighlightDarkStyle)); err != nil {
+			log.Println("Erro ao gerar CSS do tema escuro:", err)
+		}
+
+		scopedDark := cssRule.ReplaceAllString(dark.String(), "${1}"+darkThemeSelector+" .")
+		stylesheet = light.String() + scopedDark
+	})
+
+	return stylesheet
+}
@@ -0,0 +1,66 @@
+// Renderização de Markdown (CommonMark + GFM) para HTML sanitizado
+package render
+
+import (
+	"bytes"
+	"log"
+	"regexp"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// Prefixo das classes geradas pelo destaque de sintaxe
+const classPrefix = "chroma-"
+
+var highlightClasses = regexp.MustCompile(`^` + classPrefix + `[a-z0-9-]+( ` + classPrefix + `[a-z0-9-]+)*$`)
+
+var (
+	markdown = goldmark.New(
+		goldmark.WithExtensions(
+			extension.GFM,
+			// Blocos de código destacados no servidor com classes CSS (ver Stylesheet),
+			// adivinhando a linguagem quando o bloco não a declara
+			highlighting.NewHighlighting(
+				highlighting.WithGuessLanguage(true),
+				highlighting.WithFormatOptions(chromahtml.WithClasses(true), chromahtml.ClassPrefix(classPrefix)),
+			),
+		),
+		// O HTML bruto é mantido aqui e filtrado pela allowlist do sanitizador
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	policy = newPolicy()
+)
+
+// newPolicy cria a allowlist de tags e atributos aceitos no HTML final.
+// Scripts, estilos e atributos de evento (onclick, onerror...) são sempre removidos.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+
+	// Caixas de seleção das listas de tarefas do GFM
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+
+	// Apenas as classes do destaque de sintaxe, todas com o prefixo próprio; classes
+	// do frontend permitiriam cobrir ou imitar a interface com HTML bruto no texto
+	p.AllowAttrs("class").Matching(highlightClasses).OnElements("pre", "code", "span")
+
+	return p
+}
+
+// Markdown converte o texto em HTML sanitizado
+func Markdown(source string) string {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		log.Println("Erro ao renderizar markdown:", err)
+		return policy.Sanitize(source)
+	}
+
+	return string(policy.SanitizeBytes(buf.Bytes()))
+}
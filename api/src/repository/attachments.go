@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"api/src/model"
+	"api/src/storage"
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// Erro retornado quando o anexo ultrapassaria a cota do usuário
+var ErrQuotaExceeded = errors.New("cota de armazenamento excedida")
+
+type AttachmentsRepository struct {
+	db *sql.DB
+}
+
+// Cria um novo repositório de anexos
+func NewAttachmentsRepository(db *sql.DB) *AttachmentsRepository {
+	return &AttachmentsRepository{db}
+}
+
+// Retorna quantos bytes o usuário já ocupa com anexos
+func (r AttachmentsRepository) UsedBytes(ownerID uint64) (int64, error) {
+	var used int64
+	err := r.db.QueryRow("SELECT COALESCE(SUM(size), 0) FROM attachments WHERE owner_id = ?", ownerID).Scan(&used)
+	return used, err
+}
+
+// Registra um anexo, garantindo que o total do usuário não passe da cota.
+// A linha do usuário é travada para que uploads simultâneos não furem o limite.
+func (r AttachmentsRepository) Create(attachment model.Attachment, quota int64) (uint64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var lockedID uint64
+	if err := tx.QueryRow("SELECT id FROM users WHERE id = ? FOR UPDATE", attachment.OwnerID).Scan(&lockedID); err != nil {
+		return 0, err
+	}
+
+	var used int64
+	if err := tx.QueryRow("SELECT COALESCE(SUM(size), 0) FROM attachments WHERE owner_id = ?", attachment.OwnerID).Scan(&used); err != nil {
+		return 0, err
+	}
+	if used+attachment.Size > quota {
+		return 0, ErrQuotaExceeded
+	}
+
+	result, err := tx.Exec(`
+        INSERT INTO attachments (owner_id, post_id, comment_id, storage_key, filename, content_type, size, status, alt_text)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `, attachment.OwnerID, attachment.PostID, attachment.CommentID, attachment.StorageKey,
+		attachment.Filename, attachment.ContentType, attachment.Size, attachment.Status, attachment.AltText)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(id), tx.Commit()
+}
+
+// Busca um anexo pelo ID
+func (r AttachmentsRepository) GetByID(attachmentID uint64) (model.Attachment, error) {
+	rows, err := r.db.Query(attachmentSelect+" WHERE a.id = ?", attachmentID)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	defer rows.Close()
+
+	attachments, err := scanAttachments(rows)
+	if err != nil {
+		return model.Attachment{}, err
+	}
+	if len(attachments) == 0 {
+		return model.Attachment{}, sql.ErrNoRows
+	}
+
+	if err := loadVariants(r.db, attachments); err != nil {
+		return model.Attachment{}, err
+	}
+
+	return attachments[0], nil
+}
+
+// Conclui o processamento de uma imagem, registrando os tamanhos gerados.
+// O tamanho total passa a ser a soma das variantes, que é o que fica armazenado.
+func (r AttachmentsRepository) MarkReady(attachmentID uint64, width, height int, variants []model.AttachmentVariant) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var total int64
+	for _, variant := range variants {
+		_, err := tx.Exec(`
+            INSERT INTO attachment_variants (attachment_id, name, storage_key, width, height, size)
+            VALUES (?, ?, ?, ?, ?, ?)
+        `, attachmentID, variant.Name, variant.StorageKey, variant.Width, variant.Height, variant.Size)
+		if err != nil {
+			return err
+		}
+		total += variant.Size
+	}
+
+	_, err = tx.Exec(`
+        UPDATE attachments
+        SET status = 'ready', width = ?, height = ?, size = ?
+        WHERE id = ?
+    `, width, height, total, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Marca o processamento de uma imagem como falho
+func (r AttachmentsRepository) MarkFailed(attachmentID uint64) error {
+	_, err := r.db.Exec("UPDATE attachments SET status = 'failed' WHERE id = ?", attachmentID)
+	return err
+}
+
+// Remove o registro de um anexo (o arquivo deve ser apagado do armazenamento pelo chamador)
+func (r AttachmentsRepository) Delete(attachmentID uint64) error {
+	_, err := r.db.Exec("DELETE FROM attachments WHERE id = ?", attachmentID)
+	return err
+}
+
+// Lista anexos cujo post ou comentário foi removido definitivamente, além de
+// imagens cujo processamento falhou ou foi interrompido (por exemplo, num reinício da API)
+func (r AttachmentsRepository) GetOrphans(limit int) ([]model.Attachment, error) {
+	rows, err := r.db.Query(attachmentSelect+`
+        WHERE ((a.post_id IS NULL AND a.comment_id IS NULL)
+            OR (a.status <> 'ready' AND a.createdAt < CURRENT_TIMESTAMP - INTERVAL 1 HOUR))
+          AND (a.purge_retry_at IS NULL OR a.purge_retry_at <= CURRENT_TIMESTAMP)
+        ORDER BY a.purge_attempts, a.id
+        LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments, err := scanAttachments(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, loadVariants(r.db, attachments)
+}
+
+// Adia a remoção de um anexo órfão cujos arquivos não puderam ser apagados. O intervalo
+// dobra a cada falha, até um dia, para que ele não trave a fila dos demais.
+func (r AttachmentsRepository) DeferPurge(attachmentID uint64) error {
+	_, err := r.db.Exec(`
+        UPDATE attachments
+        SET purge_retry_at = CURRENT_TIMESTAMP + INTERVAL LEAST(POW(2, purge_attempts), 1440) MINUTE,
+            purge_attempts = purge_attempts + 1
+        WHERE id = ?
+    `, attachmentID)
+	return err
+}
+
+// Anexos dos posts informados, agrupados pelo ID do post
+func (r AttachmentsRepository) ByPosts(postIDs []uint64) (map[uint64][]model.Attachment, error) {
+	return attachmentsFor(r.db, "post_id", postIDs)
+}
+
+// Anexos dos comentários informados, agrupados pelo ID do comentário
+func (r AttachmentsRepository) ByComments(commentIDs []uint64) (map[uint64][]model.Attachment, error) {
+	return attachmentsFor(r.db, "comment_id", commentIDs)
+}
+
+const attachmentSelect = `
+    SELECT a.id, COALESCE(a.owner_id, 0), a.post_id, a.comment_id, a.storage_key, a.filename, a.content_type, a.size,
+        a.status, a.alt_text, a.width, a.height, a.createdAt
+    FROM attachments a`
+
+func scanAttachments(rows *sql.Rows) ([]model.Attachment, error) {
+	attachments := []model.Attachment{}
+
+	for rows.Next() {
+		var attachment model.Attachment
+		if err := rows.Scan(
+			&attachment.ID,
+			&attachment.OwnerID,
+			&attachment.PostID,
+			&attachment.CommentID,
+			&attachment.StorageKey,
+			&attachment.Filename,
+			&attachment.ContentType,
+			&attachment.Size,
+			&attachment.Status,
+			&attachment.AltText,
+			&attachment.Width,
+			&attachment.Height,
+			&attachment.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		attachment.URL = storage.PublicURL(attachment.StorageKey)
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// attachmentsFor busca de uma vez os anexos de vários posts ou comentários.
+// column é sempre uma constante interna ("post_id" ou "comment_id").
+func attachmentsFor(db *sql.DB, column string, ids []uint64) (map[uint64][]model.Attachment, error) {
+	grouped := map[uint64][]model.Attachment{}
+	if len(ids) == 0 {
+		return grouped, nil
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := db.Query(
+		attachmentSelect+" WHERE a."+column+" IN (?"+strings.Repeat(", ?", len(ids)-1)+") ORDER BY a.id",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments, err := scanAttachments(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := loadVariants(db, attachments); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range attachments {
+		owner := attachment.PostID
+		if column == "comment_id" {
+			owner = attachment.CommentID
+		}
+		grouped[*owner] = append(grouped[*owner], attachment)
+	}
+
+	return grouped, nil
+}
+
+// loadVariants preenche os tamanhos gerados de cada anexo com uma única consulta
+func loadVariants(db *sql.DB, attachments []model.Attachment) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(attachments))
+	index := map[uint64]int{}
+	for i, attachment := range attachments {
+		args[i] = attachment.ID
+		index[attachment.ID] = i
+	}
+
+	rows, err := db.Query(`
+        SELECT attachment_id, name, storage_key, width, height, size
+        FROM attachment_variants
+        WHERE attachment_id IN (?`+strings.Repeat(", ?", len(args)-1)+`)
+        ORDER BY attachment_id, width`,
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attachmentID uint64
+		var variant model.AttachmentVariant
+		if err := rows.Scan(&attachmentID, &variant.Name, &variant.StorageKey, &variant.Width, &variant.Height, &variant.Size); err != nil {
+			return err
+		}
+		variant.URL = storage.PublicURL(variant.StorageKey)
+
+		i := index[attachmentID]
+		attachments[i].Variants = append(attachments[i].Variants, variant)
+	}
+
+	return rows.Err()
+}
+
+// attachmentsOrEmpty evita que a lista de anexos seja serializada como null
+func attachmentsOrEmpty(attachments []model.Attachment) []model.Attachment {
+	if attachments == nil {
+		return []model.Attachment{}
+	}
+	return attachments
+}
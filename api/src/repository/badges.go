@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"api/src/model"
+	"database/sql"
+	"time"
+)
+
+// BadgesRepository acessa as conquistas dos usuários
+type BadgesRepository struct {
+	db *sql.DB
+}
+
+// Cria um novo repositório de conquistas
+func NewBadgesRepository(db *sql.DB) *BadgesRepository {
+	return &BadgesRepository{db}
+}
+
+// Regras das conquistas automáticas: condição sobre a tabela users (apelidada de "u")
+// que o usuário precisa atender para receber a conquista
+var badgeRules = []struct {
+	badge     string
+	condition string
+}{
+	{model.BadgeFirstPost, "u.posts_count >= 1"},
+	{model.BadgePopular, "u.likes_received_count >= 100"},
+	{model.BadgeMentor, `(
+        SELECT COUNT(*) FROM comments c
+        WHERE c.author_id = u.id AND c.accepted_at IS NOT NULL AND c.deleted_at IS NULL
+    ) >= 10`},
+	{model.BadgeVeteran, "u.createdAt <= NOW() - INTERVAL 1 YEAR"},
+}
+
+// Concede as conquistas automáticas a quem passou a atender suas regras, até limit
+// usuários por conquista. Retorna quantas foram concedidas e se alguma conquista
+// atingiu o limite, caso em que ainda pode haver usuários pendentes.
+func (r BadgesRepository) Award(limit int) (awarded int64, pending bool, err error) {
+	for _, rule := range badgeRules {
+		result, err := r.db.Exec(`
+            INSERT IGNORE INTO user_badges (user_id, badge)
+            SELECT u.id, ?
+            FROM users u
+            WHERE `+rule.condition+`
+              AND NOT EXISTS(SELECT 1 FROM user_badges ub WHERE ub.user_id = u.id AND ub.badge = ?)
+            LIMIT ?
+        `, rule.badge, rule.badge, limit)
+		if err != nil {
+			return awarded, pending, err
+		}
+
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return awarded, pending, err
+		}
+
+		awarded += inserted
+		pending = pending || inserted >= int64(limit)
+	}
+
+	return awarded, pending, nil
+}
+
+// Lista o catálogo de conquistas com o total de usuários que tem cada uma
+func (r BadgesRepository) GetAll() ([]model.Badge, error) {
+	rows, err := r.db.Query("SELECT badge, COUNT(*) FROM user_badges GROUP BY badge")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holders := map[string]uint64{}
+	for rows.Next() {
+		var badge string
+		var total uint64
+		if err := rows.Scan(&badge, &total); err != nil {
+			return nil, err
+		}
+		holders[badge] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	badges := make([]model.Badge, len(model.Badges))
+	for i, badge := range model.Badges {
+		badge.Holders = holders[badge.Slug]
+		badges[i] = badge
+	}
+
+	return badges, nil
+}
+
+// Lista quem tem a conquista, da concessão mais recente para a mais antiga.
+// Usuários com bloqueio em relação a quem consulta ficam de fora.
+func (r BadgesRepository) GetHolders(viewerID uint64, badge string, limit, offset int) ([]model.BadgeHolder, error) {
+	notBlocked, blockArgs := notBlockedClause("u.id", viewerID)
+
+	args := append([]interface{}{badge}, blockArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(`
+        SELECT `+userColumns+`, ub.awardedAt
+        FROM user_badges ub
+        JOIN users u ON u.id = ub.user_id
+        WHERE ub.badge = ? AND `+notBlocked+`
+        ORDER BY ub.awardedAt DESC, u.id
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	holders := []model.BadgeHolder{}
+	for rows.Next() {
+		var user model.User
+		var row userRow
+		var awardedAt time.Time
+
+		if err := rows.Scan(append(userFields(&user, &row), &awardedAt)...); err != nil {
+			return nil, err
+		}
+		row.finish(&user)
+
+		holders = append(holders, model.BadgeHolder{User: user.Public(), AwardedAt: awardedAt})
+	}
+
+	return holders, rows.Err()
+}
+
+// Concede uma conquista manual ao usuário; conceder de novo não altera a original
+func (r BadgesRepository) Grant(userID uint64, badge string, grantedBy uint64) error {
+	_, err := r.db.Exec(
+		"INSERT IGNORE INTO user_badges (user_id, badge, granted_by) VALUES (?, ?, ?)",
+		userID, badge, grantedBy,
+	)
+	return err
+}
+
+// Retira uma conquista do usuário (sql.ErrNoRows se ele não a tinha)
+func (r BadgesRepository) Revoke(userID uint64, badge string) error {
+	return expectAffected(r.db.Exec("DELETE FROM user_badges WHERE user_id = ? AND badge = ?", userID, badge))
+}
+
+// userBadges busca as conquistas dos usuários, na ordem do catálogo
+func userBadges(db *sql.DB, userIDs []uint64) (map[uint64][]model.UserBadge, error) {
+	badges := map[uint64][]model.UserBadge{}
+	if len(userIDs) == 0 {
+		return badges, nil
+	}
+
+	placeholders, args := idPlaceholders(userIDs)
+
+	rows, err := db.Query(
+		"SELECT user_id, badge, awardedAt FROM user_badges WHERE user_id IN ("+placeholders+")",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	awarded := map[uint64]map[string]time.Time{}
+	for rows.Next() {
+		var userID uint64
+		var badge string
+		var awardedAt time.Time
+		if err := rows.Scan(&userID, &badge, &awardedAt); err != nil {
+			return nil, err
+		}
+		if awarded[userID] == nil {
+			awarded[userID] = map[string]time.Time{}
+		}
+		awarded[userID][badge] = awardedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for userID, held := range awarded {
+		for _, badge := range model.Badges {
+			if awardedAt, ok := held[badge.Slug]; ok {
+				badges[userID] = append(badges[userID], model.UserBadge{Slug: badge.Slug, Name: badge.Name, AwardedAt: awardedAt})
+			}
+		}
+	}
+
+	return badges, nil
+}
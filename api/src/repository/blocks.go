@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"api/src/model"
+	"database/sql"
+	"errors"
+)
+
+// Bloqueia um usuário: desfaz os follows e os reposts nos dois sentidos e descarta
+// pedidos pendentes
+func (u UserRepository) Block(blockerID, blockedID uint64) error {
+	if blockerID == blockedID {
+		return errors.New("você não pode bloquear você mesmo")
+	}
+
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)", blockedID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO blocks (blocker_id, blocked_id)
+        VALUES (?, ?)
+        ON DUPLICATE KEY UPDATE blocker_id = blocker_id
+    `, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range [][2]uint64{{blockerID, blockedID}, {blockedID, blockerID}} {
+		result, err := tx.Exec("DELETE FROM followers WHERE follower_id = ? AND following_id = ?", pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+
+		if removed, err := result.RowsAffected(); err != nil {
+			return err
+		} else if removed > 0 {
+			if err := adjustFollowCounters(tx, pair[0], pair[1], -1); err != nil {
+				return err
+			}
+			if err := revokeFollowReputation(tx, pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := deleteFollowRequest(tx, pair[0], pair[1]); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(`
+            DELETE rp FROM reposts rp
+            JOIN posts p ON p.id = rp.post_id
+            WHERE rp.user_id = ? AND p.author_id = ?
+        `, pair[0], pair[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Desfaz o bloqueio; os follows removidos não voltam
+func (u UserRepository) Unblock(blockerID, blockedID uint64) error {
+	result, err := u.db.Exec("DELETE FROM blocks WHERE blocker_id = ? AND blocked_id = ?", blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Lista os usuários bloqueados pelo usuário
+func (u UserRepository) GetBlocked(blockerID uint64) ([]model.User, error) {
+	rows, err := u.db.Query(`
+        SELECT `+userColumns+`
+        FROM users u
+        INNER JOIN blocks b ON u.id = b.blocked_id
+        WHERE b.blocker_id = ?
+        ORDER BY b.createdAt DESC
+    `, blockerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanUsers(u.db, rows)
+}
+
+// Verifica se existe bloqueio entre os dois usuários, em qualquer direção
+func (u UserRepository) IsBlocked(userID, otherID uint64) (bool, error) {
+	var blocked bool
+	err := u.db.QueryRow(`
+        SELECT EXISTS(
+            SELECT 1 FROM blocks
+            WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)
+        )
+    `, userID, otherID, otherID, userID).Scan(&blocked)
+	return blocked, err
+}
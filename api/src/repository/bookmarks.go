@@ -0,0 +1,299 @@
+package repository
+
+import (
+	"api/src/model"
+	"api/src/render"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrPostNotSaved = errors.New("a nova ordem contém posts que não estão na lista")
+
+// BookmarksRepository acessa os favoritos e as coleções dos usuários
+type BookmarksRepository struct {
+	db *sql.DB
+}
+
+// Cria um novo repositório de favoritos e coleções
+func NewBookmarksRepository(db *sql.DB) *BookmarksRepository {
+	return &BookmarksRepository{db}
+}
+
+// savedList é uma lista de posts salvos: os favoritos de um usuário ou uma coleção
+type savedList struct {
+	table       string
+	ownerColumn string
+	ownerID     uint64
+}
+
+func bookmarkList(userID uint64) savedList {
+	return savedList{"bookmarks", "user_id", userID}
+}
+
+func collectionList(collectionID uint64) savedList {
+	return savedList{"collection_posts", "collection_id", collectionID}
+}
+
+// add salva o post no topo da lista; salvar de novo mantém a posição atual
+func (l savedList) add(ex execer, postID uint64) error {
+	_, err := ex.Exec(`
+        INSERT IGNORE INTO `+l.table+` (`+l.ownerColumn+`, post_id, position)
+        SELECT ?, ?, COALESCE(MIN(position), 0) - 1
+        FROM `+l.table+`
+        WHERE `+l.ownerColumn+` = ?
+    `, l.ownerID, postID, l.ownerID)
+	return err
+}
+
+// remove tira o post da lista (sql.ErrNoRows se ele não estava nela)
+func (l savedList) remove(ex execer, postID uint64) error {
+	return expectAffected(ex.Exec("DELETE FROM "+l.table+" WHERE "+l.ownerColumn+" = ? AND post_id = ?", l.ownerID, postID))
+}
+
+// reorder coloca os posts informados no topo, nessa ordem; os demais seguem depois,
+// na ordem em que estavam. Posts que não estão na lista geram ErrPostNotSaved.
+func (l savedList) reorder(db *sql.DB, postIDs []uint64) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(
+		"SELECT post_id FROM "+l.table+" WHERE "+l.ownerColumn+" = ? ORDER BY position, createdAt DESC FOR UPDATE",
+		l.ownerID,
+	)
+	if err != nil {
+		return err
+	}
+
+	var current []uint64
+	saved := map[uint64]bool{}
+	for rows.Next() {
+		var postID uint64
+		if err := rows.Scan(&postID); err != nil {
+			rows.Close()
+			return err
+		}
+		current = append(current, postID)
+		saved[postID] = true
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	order := make([]uint64, 0, len(current))
+	placed := map[uint64]bool{}
+	for _, postID := range postIDs {
+		if !saved[postID] {
+			return ErrPostNotSaved
+		}
+		if !placed[postID] {
+			order = append(order, postID)
+			placed[postID] = true
+		}
+	}
+	for _, postID := range current {
+		if !placed[postID] {
+			order = append(order, postID)
+		}
+	}
+
+	for position, postID := range order {
+		_, err := tx.Exec(
+			"UPDATE "+l.table+" SET position = ? WHERE "+l.ownerColumn+" = ? AND post_id = ?",
+			position, l.ownerID, postID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// posts lista os posts salvos na ordem escolhida. Com includeUnavailable, posts que
+// quem consulta não pode ver aparecem sem o conteúdo; sem ele, ficam de fora.
+func (l savedList) posts(db *sql.DB, viewerID uint64, includeUnavailable bool, limit, offset int) ([]model.SavedPost, error) {
+	visible, visibleArgs := postVisibleClause(viewerID)
+
+	condition := ""
+	if !includeUnavailable {
+		condition = " AND p.id IS NOT NULL"
+	}
+
+	args := append(visibleArgs, l.ownerID, limit, offset)
+
+	rows, err := db.Query(`
+        SELECT s.post_id, s.createdAt,
+               p.id, p.title, p.content, p.author_id, u.nick, p.visibility, p.post_type, p.createdAt
+        FROM `+l.table+` s
+        LEFT JOIN posts p ON p.id = s.post_id AND `+visible+`
+        LEFT JOIN users u ON u.id = p.author_id
+        WHERE s.`+l.ownerColumn+` = ?`+condition+`
+        ORDER BY s.position, s.createdAt DESC
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	saved := []model.SavedPost{}
+	for rows.Next() {
+		var item model.SavedPost
+		var (
+			id                                         *uint64
+			title, content, nick, visibility, postType *string
+			authorID                                   *uint64
+			createdAt                                  *time.Time
+		)
+
+		if err := rows.Scan(
+			&item.PostID, &item.SavedAt,
+			&id, &title, &content, &authorID, &nick, &visibility, &postType, &createdAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if id != nil {
+			item.Available = true
+			item.Post = &model.Post{
+				ID:             *id,
+				Title:          *title,
+				Content:        *content,
+				ContentHTML:    render.Post(*id, *content),
+				AuthorID:       *authorID,
+				AuthorNickname: *nick,
+				Visibility:     *visibility,
+				Type:           *postType,
+				CreatedAt:      *createdAt,
+			}
+		}
+
+		saved = append(saved, item)
+	}
+
+	return saved, rows.Err()
+}
+
+// Salva o post nos favoritos do usuário
+func (r BookmarksRepository) AddBookmark(userID, postID uint64) error {
+	return bookmarkList(userID).add(r.db, postID)
+}
+
+// Remove o post dos favoritos (sql.ErrNoRows se ele não estava salvo)
+func (r BookmarksRepository) RemoveBookmark(userID, postID uint64) error {
+	return bookmarkList(userID).remove(r.db, postID)
+}
+
+// Lista os favoritos do usuário, inclusive os que ficaram indisponíveis
+func (r BookmarksRepository) GetBookmarks(userID uint64, limit, offset int) ([]model.SavedPost, error) {
+	return bookmarkList(userID).posts(r.db, userID, true, limit, offset)
+}
+
+// Reordena os favoritos do usuário
+func (r BookmarksRepository) ReorderBookmarks(userID uint64, postIDs []uint64) error {
+	return bookmarkList(userID).reorder(r.db, postIDs)
+}
+
+// Cria uma coleção
+func (r BookmarksRepository) CreateCollection(userID uint64, input model.CollectionInput) (uint64, error) {
+	public := input.Public != nil && *input.Public
+
+	result, err := r.db.Exec(
+		"INSERT INTO collections (user_id, name, is_public) VALUES (?, ?, ?)",
+		userID, *input.Name, public,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	return uint64(id), err
+}
+
+const collectionColumns = `c.id, c.user_id, c.name, c.is_public, c.createdAt,
+    (SELECT COUNT(*) FROM collection_posts cp WHERE cp.collection_id = c.id) AS post_count`
+
+func scanCollection(scanner interface{ Scan(...interface{}) error }) (model.Collection, error) {
+	var collection model.Collection
+	err := scanner.Scan(
+		&collection.ID,
+		&collection.UserID,
+		&collection.Name,
+		&collection.Public,
+		&collection.CreatedAt,
+		&collection.PostCount,
+	)
+	return collection, err
+}
+
+// Busca uma coleção pelo ID
+func (r BookmarksRepository) GetCollection(collectionID uint64) (model.Collection, error) {
+	return scanCollection(r.db.QueryRow("SELECT "+collectionColumns+" FROM collections c WHERE c.id = ?", collectionID))
+}
+
+// Lista as coleções do usuário, da mais recente para a mais antiga; sem
+// includePrivate, apenas as públicas
+func (r BookmarksRepository) GetCollections(userID uint64, includePrivate bool) ([]model.Collection, error) {
+	query := "SELECT " + collectionColumns + " FROM collections c WHERE c.user_id = ?"
+	if !includePrivate {
+		query += " AND c.is_public"
+	}
+
+	rows, err := r.db.Query(query+" ORDER BY c.createdAt DESC, c.id DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collections := []model.Collection{}
+	for rows.Next() {
+		collection, err := scanCollection(rows)
+		if err != nil {
+			return nil, err
+		}
+		collections = append(collections, collection)
+	}
+
+	return collections, rows.Err()
+}
+
+// Atualiza nome e visibilidade da coleção; campos nulos mantêm o valor atual
+func (r BookmarksRepository) UpdateCollection(collectionID uint64, input model.CollectionInput) error {
+	_, err := r.db.Exec(
+		"UPDATE collections SET name = COALESCE(?, name), is_public = COALESCE(?, is_public) WHERE id = ?",
+		input.Name, input.Public, collectionID,
+	)
+	return err
+}
+
+// Exclui a coleção; os posts continuam nos favoritos e nas demais coleções
+func (r BookmarksRepository) DeleteCollection(collectionID uint64) error {
+	return expectAffected(r.db.Exec("DELETE FROM collections WHERE id = ?", collectionID))
+}
+
+// Adiciona o post no topo da coleção
+func (r BookmarksRepository) AddToCollection(collectionID, postID uint64) error {
+	return collectionList(collectionID).add(r.db, postID)
+}
+
+// Remove o post da coleção (sql.ErrNoRows se ele não estava nela)
+func (r BookmarksRepository) RemoveFromCollection(collectionID, postID uint64) error {
+	return collectionList(collectionID).remove(r.db, postID)
+}
+
+// Lista os posts da coleção. O dono também vê os que ficaram indisponíveis; os demais,
+// só os posts que podem ver.
+func (r BookmarksRepository) GetCollectionPosts(viewerID uint64, collection model.Collection, limit, offset int) ([]model.SavedPost, error) {
+	return collectionList(collection.ID).posts(r.db, viewerID, collection.UserID == viewerID, limit, offset)
+}
+
+// Reordena os posts da coleção
+func (r BookmarksRepository) ReorderCollection(collectionID uint64, postIDs []uint64) error {
+	return collectionList(collectionID).reorder(r.db, postIDs)
+}
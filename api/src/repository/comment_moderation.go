@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"api/src/model"
+	"errors"
+)
+
+var (
+	ErrCommentsLocked        = errors.New("os comentários deste post estão bloqueados")
+	ErrCommentsFollowersOnly = errors.New("apenas seguidores do autor podem comentar neste post")
+)
+
+// Busca as regras de comentário de um post
+func (r PostsRepository) GetCommentSettings(postID uint64) (model.CommentSettings, error) {
+	var locked bool
+	var policy string
+
+	err := r.db.QueryRow(
+		"SELECT comments_locked, comment_policy FROM posts WHERE id = ? AND deleted_at IS NULL",
+		postID,
+	).Scan(&locked, &policy)
+
+	return model.CommentSettings{Locked: &locked, Policy: &policy}, err
+}
+
+// Atualiza as regras de comentário de um post; campos nulos mantêm o valor atual
+func (r PostsRepository) UpdateCommentSettings(postID uint64, settings model.CommentSettings) error {
+	_, err := r.db.Exec(`
+        UPDATE posts
+        SET comments_locked = COALESCE(?, comments_locked), comment_policy = COALESCE(?, comment_policy)
+        WHERE id = ? AND deleted_at IS NULL
+    `, settings.Locked, settings.Policy, postID)
+	return err
+}
+
+// Verifica se o usuário pode comentar no post. O autor do post sempre pode; os demais
+// recebem ErrCommentsLocked ou ErrCommentsFollowersOnly conforme as regras do post.
+func (r PostsRepository) CanComment(userID, postID uint64) error {
+	var authorID uint64
+	var locked, follows bool
+	var policy string
+
+	err := r.db.QueryRow(`
+        SELECT p.author_id, p.comments_locked, p.comment_policy,
+               EXISTS(SELECT 1 FROM followers f WHERE f.follower_id = ? AND f.following_id = p.author_id)
+        FROM posts p
+        WHERE p.id = ?
+    `, userID, postID).Scan(&authorID, &locked, &policy, &follows)
+	if err != nil {
+		return err
+	}
+
+	if authorID == userID {
+		return nil
+	}
+	if locked {
+		return ErrCommentsLocked
+	}
+	if policy == model.CommentPolicyFollowers && !follows {
+		return ErrCommentsFollowersOnly
+	}
+
+	return nil
+}
+
+// Busca o autor de um comentário ativo e o autor do post em que ele está
+func (repo CommentsRepository) GetModerationInfo(commentID uint64) (commentAuthorID, postAuthorID uint64, err error) {
+	err = repo.db.QueryRow(`
+        SELECT c.author_id, p.author_id
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.id = ? AND c.deleted_at IS NULL
+    `, commentID).Scan(&commentAuthorID, &postAuthorID)
+	return commentAuthorID, postAuthorID, err
+}
+
+// Oculta ou volta a exibir um comentário. Um comentário ocultado deixa de estar fixado
+// e, se era a resposta aceita de uma pergunta, de estar aceito; seu autor recebe a
+// penalidade de reputação, desfeita quando o comentário volta a ser exibido.
+// Retorna sql.ErrNoRows se o comentário já estava no estado pedido.
+func (repo CommentsRepository) SetHidden(commentID uint64, hidden bool) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	key := reputationKey(commentID)
+
+	if !hidden {
+		err := expectAffected(tx.Exec(
+			"UPDATE comments SET hidden_at = NULL WHERE id = ? AND deleted_at IS NULL AND hidden_at IS NOT NULL",
+			commentID,
+		))
+		if err != nil {
+			return err
+		}
+		if err := revokeReputation(tx, model.ReputationCommentHidden, key); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	var postAuthorID uint64
+	err = tx.QueryRow(`
+        SELECT p.author_id
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.id = ? AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+        FOR UPDATE
+    `, commentID).Scan(&postAuthorID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+        UPDATE comments
+        SET hidden_at = CURRENT_TIMESTAMP, pinned_at = NULL, accepted_at = NULL
+        WHERE id = ?
+    `, commentID)
+	if err != nil {
+		return err
+	}
+
+	if err := revokeReputation(tx, model.ReputationAnswerAccepted, key); err != nil {
+		return err
+	}
+	if err := grantReputation(tx, model.ReputationCommentHidden, key, postAuthorID, commentReputationSource, commentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Fixa o comentário no topo do seu post, substituindo o fixado anteriormente.
+// Respostas e comentários ocultos não podem ser fixados (sql.ErrNoRows).
+func (repo CommentsRepository) Pin(commentID uint64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Trava também o post, para que fixações simultâneas no mesmo post esperem a vez
+	var postID uint64
+	err = tx.QueryRow(`
+        SELECT c.post_id
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.id = ? AND c.parent_id IS NULL AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+        FOR UPDATE
+    `, commentID).Scan(&postID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE comments SET pinned_at = NULL WHERE post_id = ? AND pinned_at IS NOT NULL AND id <> ?", postID, commentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE comments SET pinned_at = COALESCE(pinned_at, CURRENT_TIMESTAMP) WHERE id = ?", commentID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Desfixa o comentário
+func (repo CommentsRepository) Unpin(commentID uint64) error {
+	return expectAffected(repo.db.Exec(
+		"UPDATE comments SET pinned_at = NULL WHERE id = ? AND pinned_at IS NOT NULL",
+		commentID,
+	))
+}
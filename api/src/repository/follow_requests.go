@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"api/src/config"
+	"api/src/model"
+	"database/sql"
+	"time"
+)
+
+// Lista os pedidos pendentes para seguir o usuário (contas privadas)
+func (u UserRepository) IncomingRequests(userID uint64) ([]model.FollowRequest, error) {
+	return u.followRequests(`
+        SELECT `+userColumns+`, fr.createdAt
+        FROM follow_requests fr
+        JOIN users u ON u.id = fr.requester_id
+        WHERE fr.target_id = ?
+        ORDER BY fr.createdAt DESC
+    `, userID)
+}
+
+// Lista os pedidos enviados pelo usuário que ainda aguardam resposta
+func (u UserRepository) OutgoingRequests(userID uint64) ([]model.FollowRequest, error) {
+	return u.followRequests(`
+        SELECT `+userColumns+`, fr.createdAt
+        FROM follow_requests fr
+        JOIN users u ON u.id = fr.target_id
+        WHERE fr.requester_id = ?
+        ORDER BY fr.createdAt DESC
+    `, userID)
+}
+
+func (u UserRepository) followRequests(query string, userID uint64) ([]model.FollowRequest, error) {
+	rows, err := u.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []model.FollowRequest{}
+	for rows.Next() {
+		var user model.User
+		var row userRow
+		var createdAt time.Time
+
+		if err := rows.Scan(append(userFields(&user, &row), &createdAt)...); err != nil {
+			return nil, err
+		}
+		row.finish(&user)
+
+		requests = append(requests, model.FollowRequest{User: user.Public(), CreatedAt: createdAt})
+	}
+
+	return requests, rows.Err()
+}
+
+// Aprova o pedido: o solicitante passa a seguir o usuário
+func (u UserRepository) ApproveRequest(userID, requesterID uint64) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	found, err := deleteFollowRequest(tx, requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return sql.ErrNoRows
+	}
+
+	result, err := tx.Exec(`
+        INSERT IGNORE INTO followers (follower_id, following_id)
+        VALUES (?, ?)
+    `, requesterID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Os contadores só mudam se o follow ainda não existia
+	if inserted, err := result.RowsAffected(); err != nil {
+		return err
+	} else if inserted > 0 {
+		if err := adjustFollowCounters(tx, requesterID, userID, 1); err != nil {
+			return err
+		}
+		if err := grantFollowReputation(tx, requesterID, userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Recusa o pedido sem avisar o solicitante
+func (u UserRepository) RejectRequest(userID, requesterID uint64) error {
+	found, err := deleteFollowRequest(u.db, requesterID, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Remove um seguidor do usuário, sem que ele precise deixar de seguir
+func (u UserRepository) RemoveFollower(userID, followerID uint64) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM followers WHERE follower_id = ? AND following_id = ?", followerID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := adjustFollowCounters(tx, followerID, userID, -1); err != nil {
+		return err
+	}
+
+	if err := revokeFollowReputation(tx, followerID, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteFollowRequest apaga um pedido pendente e informa se ele existia
+func deleteFollowRequest(ex execer, requesterID, targetID uint64) (bool, error) {
+	result, err := ex.Exec("DELETE FROM follow_requests WHERE requester_id = ? AND target_id = ?", requesterID, targetID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// acceptAllRequests aceita de uma vez os pedidos pendentes, quando a conta deixa de ser privada
+func acceptAllRequests(tx *sql.Tx, userID uint64) error {
+	statements := []string{
+		// Pedidos de quem de alguma forma já segue a conta são só descartados
+		`DELETE fr FROM follow_requests fr
+        JOIN followers f ON f.follower_id = fr.requester_id AND f.following_id = fr.target_id
+        WHERE fr.target_id = ?`,
+		`INSERT INTO followers (follower_id, following_id)
+        SELECT requester_id, target_id FROM follow_requests WHERE target_id = ?`,
+		`UPDATE users u
+        JOIN follow_requests fr ON fr.requester_id = u.id
+        SET u.following_count = u.following_count + 1
+        WHERE fr.target_id = ?`,
+		`UPDATE users
+        SET followers_count = followers_count + (SELECT COUNT(*) FROM follow_requests WHERE target_id = users.id)
+        WHERE id = ?`,
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := grantFollowRequestsReputation(tx, userID); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec("DELETE FROM follow_requests WHERE target_id = ?", userID)
+	return err
+}
+
+// grantFollowReputation dá ao usuário seguido os pontos pelo novo seguidor
+func grantFollowReputation(ex execer, followerID, followingID uint64) error {
+	return grantReputation(ex, model.ReputationFollowerGained, reputationKey(followerID, followingID), followerID, userReputationSource, followingID)
+}
+
+// revokeFollowReputation desfaz os pontos de um follow desfeito
+func revokeFollowReputation(ex execer, followerID, followingID uint64) error {
+	return revokeReputation(ex, model.ReputationFollowerGained, reputationKey(followerID, followingID))
+}
+
+// grantFollowRequestsReputation dá de uma vez os pontos pelos pedidos aceitos em
+// acceptAllRequests, no mesmo formato de grantFollowReputation
+func grantFollowRequestsReputation(tx *sql.Tx, userID uint64) error {
+	points := config.ReputationRules[model.ReputationFollowerGained]
+	if points == 0 {
+		return nil
+	}
+
+	result, err := tx.Exec(`
+        INSERT IGNORE INTO reputation_events (user_id, event, points, source_key, actor_id)
+        SELECT target_id, ?, ?, CONCAT(requester_id, ':', target_id), requester_id
+        FROM follow_requests
+        WHERE target_id = ?
+    `, model.ReputationFollowerGained, points, userID)
+	if err != nil {
+		return err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil || inserted == 0 {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE users SET reputation = reputation + ? WHERE id = ?", int64(points)*inserted, userID)
+	return err
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"api/src/model"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type MutesRepository struct {
+	db *sql.DB
+}
+
+// Cria um novo repositório de contas e palavras silenciadas
+func NewMutesRepository(db *sql.DB) *MutesRepository {
+	return &MutesRepository{db}
+}
+
+// Silencia uma conta ou, se já silenciada, troca a data de expiração
+func (r MutesRepository) MuteUser(userID, mutedID uint64, expiresAt *time.Time) error {
+	if userID == mutedID {
+		return errors.New("você não pode silenciar você mesmo")
+	}
+
+	var exists bool
+	if err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)", mutedID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return sql.ErrNoRows
+	}
+
+	_, err := r.db.Exec(`
+        INSERT INTO muted_users (user_id, muted_id, expires_at)
+        VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE expires_at = VALUES(expires_at)
+    `, userID, mutedID, expiresAt)
+	return err
+}
+
+// Encerra o silêncio de uma conta
+func (r MutesRepository) UnmuteUser(userID, mutedID uint64) error {
+	return expectAffected(r.db.Exec("DELETE FROM muted_users WHERE user_id = ? AND muted_id = ?", userID, mutedID))
+}
+
+// Lista as contas silenciadas ainda em vigor
+func (r MutesRepository) GetMutedUsers(userID uint64) ([]model.MutedUser, error) {
+	rows, err := r.db.Query(`
+        SELECT `+userColumns+`, mu.expires_at, mu.createdAt
+        FROM muted_users mu
+        JOIN users u ON u.id = mu.muted_id
+        WHERE mu.user_id = ? AND (mu.expires_at IS NULL OR mu.expires_at > CURRENT_TIMESTAMP)
+        ORDER BY mu.createdAt DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	muted := []model.MutedUser{}
+	for rows.Next() {
+		var user model.User
+		var row userRow
+		var mute model.MutedUser
+
+		if err := rows.Scan(append(userFields(&user, &row), &mute.ExpiresAt, &mute.CreatedAt)...); err != nil {
+			return nil, err
+		}
+		row.finish(&user)
+
+		mute.User = user.Public()
+		muted = append(muted, mute)
+	}
+
+	return muted, rows.Err()
+}
+
+// Silencia uma palavra, frase ou hashtag e retorna o ID criado
+func (r MutesRepository) CreateKeyword(userID uint64, keyword model.MutedKeyword) (uint64, error) {
+	result, err := r.db.Exec(`
+        INSERT INTO muted_keywords (user_id, phrase, pattern, whole_word, case_sensitive, expires_at)
+        VALUES (?, ?, ?, ?, ?, ?)
+    `, userID, keyword.Phrase, keyword.Pattern(), keyword.WholeWord, keyword.CaseSensitive, keyword.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(id), nil
+}
+
+// Altera uma palavra silenciada do usuário
+func (r MutesRepository) UpdateKeyword(userID, keywordID uint64, keyword model.MutedKeyword) error {
+	result, err := r.db.Exec(`
+        UPDATE muted_keywords
+        SET phrase = ?, pattern = ?, whole_word = ?, case_sensitive = ?, expires_at = ?
+        WHERE id = ? AND user_id = ?
+    `, keyword.Phrase, keyword.Pattern(), keyword.WholeWord, keyword.CaseSensitive, keyword.ExpiresAt, keywordID, userID)
+	if err != nil {
+		return err
+	}
+
+	// Sem mudanças o MySQL informa zero linhas afetadas: confere se a palavra existe
+	if rows, err := result.RowsAffected(); err != nil || rows > 0 {
+		return err
+	}
+	_, err = r.GetKeyword(userID, keywordID)
+	return err
+}
+
+// Remove uma palavra silenciada do usuário
+func (r MutesRepository) DeleteKeyword(userID, keywordID uint64) error {
+	return expectAffected(r.db.Exec("DELETE FROM muted_keywords WHERE id = ? AND user_id = ?", keywordID, userID))
+}
+
+// Busca uma palavra silenciada do usuário
+func (r MutesRepository) GetKeyword(userID, keywordID uint64) (model.MutedKeyword, error) {
+	var keyword model.MutedKeyword
+	err := r.db.QueryRow(`
+        SELECT id, phrase, whole_word, case_sensitive, expires_at, createdAt
+        FROM muted_keywords
+        WHERE id = ? AND user_id = ?
+    `, keywordID, userID).Scan(
+		&keyword.ID, &keyword.Phrase, &keyword.WholeWord, &keyword.CaseSensitive, &keyword.ExpiresAt, &keyword.CreatedAt,
+	)
+	return keyword, err
+}
+
+// Lista as palavras silenciadas ainda em vigor
+func (r MutesRepository) GetKeywords(userID uint64) ([]model.MutedKeyword, error) {
+	rows, err := r.db.Query(`
+        SELECT id, phrase, whole_word, case_sensitive, expires_at, createdAt
+        FROM muted_keywords
+        WHERE user_id = ? AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+        ORDER BY createdAt DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keywords := []model.MutedKeyword{}
+	for rows.Next() {
+		var keyword model.MutedKeyword
+		err := rows.Scan(
+			&keyword.ID, &keyword.Phrase, &keyword.WholeWord, &keyword.CaseSensitive, &keyword.ExpiresAt, &keyword.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		keywords = append(keywords, keyword)
+	}
+
+	return keywords, rows.Err()
+}
+
+// Apaga os silêncios vencidos e retorna quantas linhas foram removidas
+func (r MutesRepository) PurgeExpired(limit int) (int64, error) {
+	var total int64
+
+	for _, table := range []string{"muted_users", "muted_keywords"} {
+		result, err := r.db.Exec(
+			"DELETE FROM "+table+" WHERE expires_at IS NOT NULL AND expires_at <= CURRENT_TIMESTAMP LIMIT ?",
+			limit,
+		)
+		if err != nil {
+			return total, err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += rows
+	}
+
+	return total, nil
+}
+
+// expectAffected converte um DELETE/UPDATE que não encontrou linhas em sql.ErrNoRows
+func expectAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"api/src/avatar"
 	"api/src/model"
+	"api/src/render"
 	"database/sql"
 	"time"
 )
@@ -22,11 +24,20 @@ func NewCommentsRepository(db *sql.DB) *CommentsRepository {
 }
 
 func (r PostsRepository) Create(post model.Post) (uint64, error) {
-	result, err := r.db.Exec(
-		"INSERT INTO posts (title, content, author_id) VALUES (?, ?, ?)",
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		"INSERT INTO posts (title, content, author_id, visibility, post_type, quoted_post_id) VALUES (?, ?, ?, ?, ?, ?)",
 		post.Title,
 		post.Content,
 		post.AuthorID,
+		post.Visibility,
+		post.Type,
+		post.QuotedPostID,
 	)
 	if err != nil {
 		return 0, err
@@ -37,10 +48,34 @@ func (r PostsRepository) Create(post model.Post) (uint64, error) {
 		return 0, err
 	}
 
-	return uint64(postID), nil
+	if err := adjustPostCounters(tx, uint64(postID), 1); err != nil {
+		return 0, err
+	}
+
+	return uint64(postID), tx.Commit()
 }
 
-func (r PostsRepository) GetAll(userID uint64) ([]map[string]interface{}, error) {
+// Feed do usuário: os posts visíveis e, de quem ele segue (e dele mesmo), os reposts,
+// que repetem o post original na data do repost com a atribuição em "reposted_by"
+func (r PostsRepository) GetAll(userID uint64, filter model.FeedFilter) ([]map[string]interface{}, error) {
+	feed, feedArgs := postFeedClause(userID)
+
+	if filter.Type != "" {
+		feed += " AND p.post_type = ?"
+		feedArgs = append(feedArgs, filter.Type)
+	}
+	if filter.Unanswered {
+		feed += " AND p.post_type = 'question' AND NOT " + answeredExpr
+	}
+
+	reposterBlocked, reposterBlockArgs := notBlockedClause("fi.reposted_by", userID)
+	reposterMuted, reposterMutedArgs := notMutedClause(userID, "fi.reposted_by", "CONCAT_WS(' ', p.title, p.content)")
+
+	args := append([]interface{}{userID, userID, userID}, feedArgs...)
+	args = append(args, userID, userID)
+	args = append(args, reposterBlockArgs...)
+	args = append(args, reposterMutedArgs...)
+
 	rows, err := r.db.Query(`
         SELECT 
             p.id,
@@ -48,17 +83,58 @@ func (r PostsRepository) GetAll(userID uint64) ([]map[string]interface{}, error)
             p.content,
             p.author_id,
             u.nick AS author_nickname,
+            u.avatar_key,
+            u.avatar_variants,
+            p.visibility,
+            p.post_type,
+            `+answeredColumn+`,
             p.createdAt,
-            COUNT(l.post_id) AS likes,
+            p.edited_at,
+            (SELECT COUNT(*) FROM post_revisions WHERE post_id = p.id) AS revision_count,
+            (SELECT COUNT(*) FROM likes WHERE post_id = p.id) AS likes,
             EXISTS(
                 SELECT 1 FROM likes WHERE user_id = ? AND post_id = p.id
-            ) AS likedByUser
-        FROM posts p
+            ) AS likedByUser,
+            EXISTS(
+                SELECT 1 FROM bookmarks WHERE user_id = ? AND post_id = p.id
+            ) AS bookmarkedByMe,
+            p.quoted_post_id,
+            (SELECT COUNT(*) FROM reposts WHERE post_id = p.id) AS reposts,
+            EXISTS(
+                SELECT 1 FROM reposts WHERE user_id = ? AND post_id = p.id
+            ) AS repostedByUser,
+            fi.reposted_by,
+            ru.nick AS reposter_nickname,
+            fi.feed_at
+        FROM (
+            -- Cada post entra uma vez, na posição da entrada mais recente (a publicação
+            -- ou um repost visível) e atribuído ao autor desse repost
+            SELECT fi.post_id, fi.reposted_by, fi.feed_at,
+                   ROW_NUMBER() OVER (
+                       PARTITION BY fi.post_id
+                       ORDER BY fi.feed_at DESC, fi.reposted_by IS NULL, fi.reposted_by
+                   ) AS feed_rank
+            FROM (
+                SELECT id AS post_id, NULL AS reposted_by, createdAt AS feed_at FROM posts
+                UNION ALL
+                SELECT post_id, user_id, createdAt FROM reposts
+            ) fi
+            JOIN posts p ON p.id = fi.post_id
+            WHERE `+feed+`
+              AND (fi.reposted_by IS NULL OR (
+                  (fi.reposted_by = ? OR EXISTS(
+                      SELECT 1 FROM followers rf WHERE rf.follower_id = ? AND rf.following_id = fi.reposted_by
+                  ))
+                  AND `+reposterBlocked+`
+                  AND `+reposterMuted+`
+              ))
+        ) fi
+        JOIN posts p ON p.id = fi.post_id
         LEFT JOIN users u ON u.id = p.author_id
-        LEFT JOIN likes l ON l.post_id = p.id
-        GROUP BY p.id
-        ORDER BY p.createdAt DESC
-    `, userID)
+        LEFT JOIN users ru ON ru.id = fi.reposted_by
+        WHERE fi.feed_rank = 1
+        ORDER BY fi.feed_at DESC, p.id DESC
+    `, args...)
 
 	if err != nil {
 		return nil, err
@@ -66,6 +142,8 @@ func (r PostsRepository) GetAll(userID uint64) ([]map[string]interface{}, error)
 	defer rows.Close()
 
 	var posts []map[string]interface{}
+	var postIDs []uint64
+	var quoted []*uint64
 
 	for rows.Next() {
 		var (
@@ -74,35 +152,106 @@ func (r PostsRepository) GetAll(userID uint64) ([]map[string]interface{}, error)
 			content        string
 			authorId       uint64
 			authorNickname string
+			authorAvatar   *string
+			avatarVariants *string
+			visibility     string
+			postType       string
+			answered       bool
 			createdAt      time.Time
+			editedAt       *time.Time
+			revisionCount  uint64
 			likes          uint64
 			likedByUser    bool
+			bookmarkedByMe bool
+			quotedPostID   *uint64
+			reposts        uint64
+			repostedByUser bool
+			repostedBy     *uint64
+			reposterNick   *string
+			feedAt         time.Time
 		)
 
-		err := rows.Scan(&id, &title, &content, &authorId, &authorNickname, &createdAt, &likes, &likedByUser)
+		err := rows.Scan(
+			&id, &title, &content, &authorId, &authorNickname, &authorAvatar, &avatarVariants, &visibility, &postType, &answered,
+			&createdAt, &editedAt, &revisionCount, &likes, &likedByUser, &bookmarkedByMe,
+			&quotedPostID, &reposts, &repostedByUser, &repostedBy, &reposterNick, &feedAt,
+		)
 		if err != nil {
 			return nil, err
 		}
 
+		var attribution *model.RepostedBy
+		if repostedBy != nil {
+			attribution = &model.RepostedBy{UserID: *repostedBy, RepostedAt: feedAt}
+			if reposterNick != nil {
+				attribution.Nickname = *reposterNick
+			}
+		}
+
+		// "likes" e "likedByUser" consideram qualquer reação, para clientes que só conhecem o like
 		post := map[string]interface{}{
-			"id":              id,
-			"title":           title,
-			"content":         content,
-			"author_id":       authorId,
-			"author_nickname": authorNickname,
-			"created_at":      createdAt,
-			"likes":           likes,
-			"likedByUser":     likedByUser,
+			"id":               id,
+			"title":            title,
+			"content":          content,
+			"content_html":     render.Post(id, content),
+			"author_id":        authorId,
+			"author_nickname":  authorNickname,
+			"author_photo_url": avatar.URL(authorId, authorAvatar, avatarVariants),
+			"visibility":       visibility,
+			"type":             postType,
+			"answered":         answered,
+			"created_at":       createdAt,
+			"edited_at":        editedAt,
+			"revision_count":   revisionCount,
+			"likes":            likes,
+			"likedByUser":      likedByUser,
+			"bookmarkedByMe":   bookmarkedByMe,
+			"quoted_post_id":   quotedPostID,
+			"reposts":          reposts,
+			"repostedByUser":   repostedByUser,
+			"reposted_by":      attribution,
 		}
 
 		posts = append(posts, post)
+		postIDs = append(postIDs, id)
+		quoted = append(quoted, quotedPostID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attachments, err := attachmentsFor(r.db, "post_id", postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reactions, err := postReactionSummaries(r.db, userID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	quotedByID, err := quotedPosts(r.db, userID, quotedIDs(quoted))
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range postIDs {
+		posts[i]["attachments"] = attachmentsOrEmpty(attachments[id])
+		posts[i]["reactions"] = reactions[id].Reactions
+		posts[i]["my_reaction"] = reactions[id].MyReaction
+		if quoted[i] != nil {
+			posts[i]["quoted_post"] = quotedByID[*quoted[i]]
+		}
 	}
 
 	return posts, nil
 }
 
-// Buscar post por ID
-func (r PostsRepository) GetByID(postID uint64) (model.Post, error) {
+// Buscar post por ID, desde que visível para o usuário (o autor também enxerga seus rascunhos)
+func (r PostsRepository) GetByID(userID, postID uint64) (model.Post, error) {
+	visible, visibleArgs := postOwnedOrVisibleClause(userID)
+
 	query := `
         SELECT 
             p.id, 
@@ -110,14 +259,23 @@ func (r PostsRepository) GetByID(postID uint64) (model.Post, error) {
             p.content, 
             p.author_id, 
             u.nick AS author_nickname,
+            p.visibility,
+            p.post_type,
+            ` + answeredColumn + `,
+            p.status,
+            p.publish_at,
+            p.edited_at,
+            (SELECT COUNT(*) FROM post_revisions WHERE post_id = p.id) AS revision_count,
+            p.quoted_post_id,
             p.createdAt        -- CORRIGIDO
         FROM posts p
         LEFT JOIN users u ON u.id = p.author_id
-        WHERE p.id = ?
+        WHERE p.id = ? AND ` + visible + `
         LIMIT 1
     `
 
-	row := r.db.QueryRow(query, postID)
+	args := append([]interface{}{postID}, visibleArgs...)
+	row := r.db.QueryRow(query, args...)
 
 	var post model.Post
 
@@ -127,6 +285,14 @@ func (r PostsRepository) GetByID(postID uint64) (model.Post, error) {
 		&post.Content,
 		&post.AuthorID,
 		&post.AuthorNickname,
+		&post.Visibility,
+		&post.Type,
+		&post.Answered,
+		&post.Status,
+		&post.PublishAt,
+		&post.EditedAt,
+		&post.RevisionCount,
+		&post.QuotedPostID,
 		&post.CreatedAt,
 	)
 
@@ -134,45 +300,428 @@ func (r PostsRepository) GetByID(postID uint64) (model.Post, error) {
 		return model.Post{}, err
 	}
 
+	post.ContentHTML = render.Post(post.ID, post.Content)
+
+	attachments, err := attachmentsFor(r.db, "post_id", []uint64{post.ID})
+	if err != nil {
+		return model.Post{}, err
+	}
+	post.Attachments = attachmentsOrEmpty(attachments[post.ID])
+
+	if post.QuotedPostID != nil {
+		quoted, err := quotedPosts(r.db, userID, []uint64{*post.QuotedPostID})
+		if err != nil {
+			return model.Post{}, err
+		}
+		post.QuotedPost = quoted[*post.QuotedPostID]
+	}
+
 	return post, nil
 }
 
-// Atualiza post
-func (r PostsRepository) Update(postID uint64, post model.Post) error {
-	statement, err := r.db.Prepare("UPDATE posts SET title = ?, content = ? WHERE id = ?")
+// Cria um rascunho sem exigir título ou conteúdo
+func (r PostsRepository) CreateDraft(post model.Post) (uint64, error) {
+	result, err := r.db.Exec(
+		"INSERT INTO posts (title, content, author_id, visibility, post_type, quoted_post_id, status) VALUES (?, ?, ?, ?, ?, ?, 'draft')",
+		post.Title,
+		post.Content,
+		post.AuthorID,
+		post.Visibility,
+		post.Type,
+		post.QuotedPostID,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	postID, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(postID), nil
+}
+
+// Salva parcialmente um rascunho do autor; campos nulos mantêm o valor atual
+func (r PostsRepository) SaveDraft(authorID, postID uint64, draft model.DraftPatch) error {
+	result, err := r.db.Exec(`
+        UPDATE posts
+        SET title = COALESCE(?, title),
+            content = COALESCE(?, content),
+            visibility = COALESCE(?, visibility),
+            post_type = COALESCE(?, post_type),
+            quoted_post_id = COALESCE(?, quoted_post_id)
+        WHERE id = ? AND author_id = ? AND status IN ('draft', 'scheduled') AND deleted_at IS NULL
+    `, draft.Title, draft.Content, draft.Visibility, draft.Type, draft.QuotedPostID, postID, authorID)
 	if err != nil {
 		return err
 	}
-	defer statement.Close()
-	_, err = statement.Exec(post.Title, post.Content, postID)
+
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+
+	if rows == 0 {
+		// Sem alterações também resulta em 0 linhas; confirma se o rascunho existe
+		var exists bool
+		err = r.db.QueryRow(
+			"SELECT EXISTS(SELECT 1 FROM posts WHERE id = ? AND author_id = ? AND status IN ('draft', 'scheduled') AND deleted_at IS NULL)",
+			postID, authorID,
+		).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return sql.ErrNoRows
+		}
+	}
+
 	return nil
 }
 
-// Deletar post
-func (r PostsRepository) Delete(postID uint64) error {
-	query := "DELETE FROM posts WHERE id = ?"
+// Lista os rascunhos e posts agendados do autor, do mais recente para o mais antigo
+func (r PostsRepository) GetDrafts(authorID uint64) ([]model.Post, error) {
+	rows, err := r.db.Query(`
+        SELECT p.id, p.title, p.content, p.author_id, u.nick, p.visibility, p.post_type, p.quoted_post_id, p.status, p.publish_at, p.createdAt
+        FROM posts p
+        LEFT JOIN users u ON u.id = p.author_id
+        WHERE p.author_id = ? AND p.status IN ('draft', 'scheduled') AND p.deleted_at IS NULL
+        ORDER BY p.id DESC
+    `, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drafts := []model.Post{}
 
-	_, err := r.db.Exec(query, postID)
+	for rows.Next() {
+		var post model.Post
+		if err := rows.Scan(
+			&post.ID,
+			&post.Title,
+			&post.Content,
+			&post.AuthorID,
+			&post.AuthorNickname,
+			&post.Visibility,
+			&post.Type,
+			&post.QuotedPostID,
+			&post.Status,
+			&post.PublishAt,
+			&post.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		post.ContentHTML = render.Post(post.ID, post.Content)
+		drafts = append(drafts, post)
+	}
+
+	return drafts, rows.Err()
+}
+
+// Publica imediatamente um rascunho (ou post agendado) já validado; a data de criação passa a ser a da publicação
+func (r PostsRepository) Publish(postID uint64, post model.Post) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        UPDATE posts
+        SET title = ?, content = ?, visibility = ?, status = 'published', publish_at = NULL, createdAt = CURRENT_TIMESTAMP
+        WHERE id = ? AND author_id = ? AND status IN ('draft', 'scheduled') AND deleted_at IS NULL
+    `, post.Title, post.Content, post.Visibility, postID, post.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := adjustPostCounters(tx, postID, 1); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Agenda (ou reagenda) a publicação de um rascunho já validado
+func (r PostsRepository) Schedule(postID uint64, post model.Post, publishAt time.Time) error {
+	result, err := r.db.Exec(`
+        UPDATE posts
+        SET title = ?, content = ?, visibility = ?, status = 'scheduled', publish_at = ?
+        WHERE id = ? AND author_id = ? AND status IN ('draft', 'scheduled') AND deleted_at IS NULL
+    `, post.Title, post.Content, post.Visibility, publishAt, postID, post.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Cancela o agendamento; o post volta a ser um rascunho
+func (r PostsRepository) CancelSchedule(authorID, postID uint64) error {
+	result, err := r.db.Exec(`
+        UPDATE posts
+        SET status = 'draft', publish_at = NULL
+        WHERE id = ? AND author_id = ? AND status = 'scheduled' AND deleted_at IS NULL
+    `, postID, authorID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
 	return nil
 }
 
-// Dar like
+// Publica os posts agendados cuja data já passou e retorna os IDs publicados.
+// As linhas são travadas com SKIP LOCKED, então várias instâncias da API podem
+// rodar ao mesmo tempo sem publicar o mesmo post duas vezes.
+func (r PostsRepository) PublishDue(limit int) ([]uint64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        SELECT id FROM posts
+        WHERE status = 'scheduled' AND publish_at <= CURRENT_TIMESTAMP AND deleted_at IS NULL
+        ORDER BY publish_at
+        LIMIT ?
+        FOR UPDATE SKIP LOCKED
+    `, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		_, err := tx.Exec(`
+            UPDATE posts
+            SET status = 'published', createdAt = publish_at, publish_at = NULL
+            WHERE id = ?
+        `, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := adjustPostCounters(tx, id, 1); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Atualiza post. Em posts publicados, cada alteração de título ou conteúdo
+// gera uma revisão com o editor e a data da edição.
+func (r PostsRepository) Update(postID, editorID uint64, post model.Post) error {
+	defer render.InvalidatePost(postID)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current model.Post
+	err = tx.QueryRow(
+		"SELECT title, content, author_id, status, createdAt FROM posts WHERE id = ? FOR UPDATE",
+		postID,
+	).Scan(&current.Title, &current.Content, &current.AuthorID, &current.Status, &current.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	changed := current.Title != post.Title || current.Content != post.Content
+	if current.Status != model.PostStatusPublished || !changed {
+		_, err = tx.Exec(`
+            UPDATE posts
+            SET title = ?, content = ?, visibility = COALESCE(NULLIF(?, ''), visibility)
+            WHERE id = ?
+        `, post.Title, post.Content, post.Visibility, postID)
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	// Posts anteriores ao histórico ganham a versão original como primeira revisão
+	var revisions uint64
+	if err = tx.QueryRow("SELECT COUNT(*) FROM post_revisions WHERE post_id = ?", postID).Scan(&revisions); err != nil {
+		return err
+	}
+	if revisions == 0 {
+		_, err = tx.Exec(
+			"INSERT INTO post_revisions (post_id, editor_id, title, content, createdAt) VALUES (?, ?, ?, ?, ?)",
+			postID, current.AuthorID, current.Title, current.Content, current.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+        UPDATE posts
+        SET title = ?, content = ?, visibility = COALESCE(NULLIF(?, ''), visibility), edited_at = CURRENT_TIMESTAMP
+        WHERE id = ?
+    `, post.Title, post.Content, post.Visibility, postID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO post_revisions (post_id, editor_id, title, content) VALUES (?, ?, ?, ?)",
+		postID, editorID, post.Title, post.Content,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Move o post para a lixeira; curtidas e comentários são preservados para uma restauração
+func (r PostsRepository) Delete(postID uint64) error {
+	defer render.InvalidatePost(postID)
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Os contadores são ajustados antes, enquanto o post ainda conta como publicado
+	var inTrash bool
+	err = tx.QueryRow("SELECT deleted_at IS NOT NULL FROM posts WHERE id = ? FOR UPDATE", postID).Scan(&inTrash)
+	if err != nil {
+		return err
+	}
+	if inTrash {
+		return nil
+	}
+
+	if err := adjustPostCounters(tx, postID, -1); err != nil {
+		return err
+	}
+
+	query := "UPDATE posts SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL"
+
+	_, err = tx.Exec(query, postID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Dar like: registra a reação 👍 (falha se o usuário já reagiu ao post)
 func (r PostsRepository) LikePost(userID, postID uint64) error {
-	_, err := r.db.Exec("INSERT INTO likes (user_id, post_id) VALUES (?, ?)", userID, postID)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT INTO likes (user_id, post_id, reaction) VALUES (?, ?, ?)", userID, postID, model.LikeReaction); err != nil {
+		return err
+	}
+
+	if err := adjustLikeCounter(tx, postID, 1); err != nil {
+		return err
+	}
+
+	if err := grantReputation(tx, model.ReputationPostLiked, reputationKey(postID, userID), userID, postReputationSource, postID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Remover like
+// Remover like: remove a reação do usuário ao post, qualquer que seja o emoji
 func (r PostsRepository) UnlikePost(userID, postID uint64) error {
-	_, err := r.db.Exec("DELETE FROM likes WHERE user_id = ? AND post_id = ?", userID, postID)
-	return err
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM likes WHERE user_id = ? AND post_id = ?", userID, postID)
+	if err != nil {
+		return err
+	}
+
+	if removed, err := result.RowsAffected(); err != nil || removed == 0 {
+		return err
+	}
+
+	if err := adjustLikeCounter(tx, postID, -1); err != nil {
+		return err
+	}
+
+	if err := revokeReputation(tx, model.ReputationPostLiked, reputationKey(postID, userID)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Verifica se o post existe e é visível para o usuário
+func (r PostsRepository) CanView(userID, postID uint64) (bool, error) {
+	visible, visibleArgs := postVisibleClause(userID)
+
+	args := append([]interface{}{postID}, visibleArgs...)
+
+	var exists bool
+	err := r.db.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM posts p WHERE p.id = ? AND "+visible+")",
+		args...,
+	).Scan(&exists)
+	return exists, err
 }
 
 // Contar likes
@@ -182,6 +731,10 @@ func (r PostsRepository) CountLikes(postID uint64) (uint64, error) {
 	return total, err
 }
 func (r PostsRepository) GetPostWithLikeInfo(userID, postID uint64) (map[string]interface{}, error) {
+	visible, visibleArgs := postVisibleClause(userID)
+
+	args := append([]interface{}{userID, userID, postID}, visibleArgs...)
+
 	row := r.db.QueryRow(`
         SELECT 
             p.id,
@@ -189,16 +742,26 @@ func (r PostsRepository) GetPostWithLikeInfo(userID, postID uint64) (map[string]
             p.content,
             p.author_id,
             u.nick AS author_nickname,
+            u.avatar_key,
+            u.avatar_variants,
+            p.visibility,
+            p.post_type,
+            `+answeredColumn+`,
             p.createdAt,
+            p.edited_at,
+            (SELECT COUNT(*) FROM post_revisions WHERE post_id = p.id) AS revision_count,
             (SELECT COUNT(*) FROM likes WHERE post_id = p.id) AS likes,
             EXISTS(
                 SELECT 1 FROM likes WHERE user_id = ? AND post_id = p.id
-            ) AS likedByUser
+            ) AS likedByUser,
+            EXISTS(
+                SELECT 1 FROM bookmarks WHERE user_id = ? AND post_id = p.id
+            ) AS bookmarkedByMe
         FROM posts p
         LEFT JOIN users u ON u.id = p.author_id
-        WHERE p.id = ?
+        WHERE p.id = ? AND `+visible+`
         LIMIT 1
-    `, userID, postID)
+    `, args...)
 
 	var (
 		id             uint64
@@ -206,34 +769,63 @@ func (r PostsRepository) GetPostWithLikeInfo(userID, postID uint64) (map[string]
 		content        string
 		authorID       uint64
 		authorNickname string
+		authorAvatar   *string
+		avatarVariants *string
+		visibility     string
+		postType       string
+		answered       bool
 		createdAt      time.Time
+		editedAt       *time.Time
+		revisionCount  uint64
 		likes          uint64
 		likedByUser    bool
+		bookmarkedByMe bool
 	)
 
-	err := row.Scan(&id, &title, &content, &authorID, &authorNickname, &createdAt, &likes, &likedByUser)
+	err := row.Scan(&id, &title, &content, &authorID, &authorNickname, &authorAvatar, &avatarVariants, &visibility, &postType, &answered, &createdAt, &editedAt, &revisionCount, &likes, &likedByUser, &bookmarkedByMe)
 	if err != nil {
 		return nil, err
 	}
 
 	post := map[string]interface{}{
-		"id":              id,
-		"title":           title,
-		"content":         content,
-		"author_id":       authorID,
-		"author_nickname": authorNickname,
-		"created_at":      createdAt,
-		"likes":           likes,
-		"likedByUser":     likedByUser,
+		"id":               id,
+		"title":            title,
+		"content":          content,
+		"content_html":     render.Post(id, content),
+		"author_id":        authorID,
+		"author_nickname":  authorNickname,
+		"author_photo_url": avatar.URL(authorID, authorAvatar, avatarVariants),
+		"visibility":       visibility,
+		"type":             postType,
+		"answered":         answered,
+		"created_at":       createdAt,
+		"edited_at":        editedAt,
+		"revision_count":   revisionCount,
+		"likes":            likes,
+		"likedByUser":      likedByUser,
+		"bookmarkedByMe":   bookmarkedByMe,
 	}
 
+	attachments, err := attachmentsFor(r.db, "post_id", []uint64{id})
+	if err != nil {
+		return nil, err
+	}
+	post["attachments"] = attachmentsOrEmpty(attachments[id])
+
+	reactions, err := postReactionSummaries(r.db, userID, []uint64{id})
+	if err != nil {
+		return nil, err
+	}
+	post["reactions"] = reactions[id].Reactions
+	post["my_reaction"] = reactions[id].MyReaction
+
 	return post, nil
 }
 
 // Criar comentário
 func (repo CommentsRepository) Create(comment model.Comment) (uint64, error) {
-	query := "INSERT INTO comments (post_id, author_id, content) VALUES (?, ?, ?)"
-	result, err := repo.db.Exec(query, comment.PostID, comment.AuthorID, comment.Content)
+	query := "INSERT INTO comments (post_id, parent_id, depth, author_id, content) VALUES (?, ?, ?, ?, ?)"
+	result, err := repo.db.Exec(query, comment.PostID, comment.ParentID, comment.Depth, comment.AuthorID, comment.Content)
 
 	if err != nil {
 		return 0, err
@@ -247,86 +839,231 @@ func (repo CommentsRepository) Create(comment model.Comment) (uint64, error) {
 	return uint64(id), nil
 }
 
-// Deletar comentário
-func (repo CommentsRepository) Delete(commentID uint64) error {
-	_, err := repo.db.Exec("DELETE FROM comments WHERE id = ?", commentID)
-	return err
+// Move o comentário para a lixeira, registrando quem o excluiu (o autor do comentário
+// ou o autor do post). Um comentário excluído deixa de estar fixado; se quem excluiu
+// foi o autor do post, o autor do comentário recebe a penalidade de reputação.
+func (repo CommentsRepository) Delete(commentID, deletedBy uint64) error {
+	defer render.InvalidateComment(commentID)
+
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        UPDATE comments
+        SET deleted_at = CURRENT_TIMESTAMP, deleted_by = ?, pinned_at = NULL
+        WHERE id = ? AND deleted_at IS NULL
+    `, deletedBy, commentID)
+	if err != nil {
+		return err
+	}
+
+	if deleted, err := result.RowsAffected(); err != nil || deleted == 0 {
+		return err
+	}
+
+	err = grantReputation(tx, model.ReputationCommentRemoved, reputationKey(commentID), deletedBy, commentReputationSource, commentID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Busca post, autor e data de criação de um comentário ativo, usados para liberar a edição
+func (repo CommentsRepository) GetEditInfo(commentID uint64) (postID, authorID uint64, createdAt time.Time, err error) {
+	err = repo.db.QueryRow(
+		"SELECT post_id, author_id, createdAt FROM comments WHERE id = ? AND deleted_at IS NULL",
+		commentID,
+	).Scan(&postID, &authorID, &createdAt)
+	return postID, authorID, createdAt, err
+}
+
+// Edita o texto do comentário. Cada alteração gera uma revisão com o editor e a data.
+func (repo CommentsRepository) Update(commentID, editorID uint64, content string) error {
+	defer render.InvalidateComment(commentID)
+
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var current model.Comment
+	var createdAt time.Time
+	err = tx.QueryRow(
+		"SELECT author_id, content, createdAt FROM comments WHERE id = ? AND deleted_at IS NULL FOR UPDATE",
+		commentID,
+	).Scan(&current.AuthorID, &current.Content, &createdAt)
+	if err != nil {
+		return err
+	}
+
+	if current.Content == content {
+		return tx.Commit()
+	}
+
+	// Comentários sem histórico ganham o texto original como primeira revisão
+	var revisions uint64
+	if err = tx.QueryRow("SELECT COUNT(*) FROM comment_revisions WHERE comment_id = ?", commentID).Scan(&revisions); err != nil {
+		return err
+	}
+	if revisions == 0 {
+		_, err = tx.Exec(
+			"INSERT INTO comment_revisions (comment_id, editor_id, content, createdAt) VALUES (?, ?, ?, ?)",
+			commentID, current.AuthorID, current.Content, createdAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec("UPDATE comments SET content = ?, edited_at = CURRENT_TIMESTAMP WHERE id = ?", content, commentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		"INSERT INTO comment_revisions (comment_id, editor_id, content) VALUES (?, ?, ?)",
+		commentID, editorID, content,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Busca um comentário ativo pelo ID, com o voto de quem consulta
+func (repo CommentsRepository) GetByID(viewerID, commentID uint64) (model.Comment, error) {
+	var comment model.Comment
+
+	err := repo.db.QueryRow(`
+        SELECT c.id, c.post_id, c.parent_id, c.depth, c.author_id, c.content, c.createdAt, c.edited_at,
+               c.hidden_at IS NOT NULL, c.pinned_at IS NOT NULL, c.score, c.accepted_at IS NOT NULL,
+               COALESCE((SELECT v.value FROM comment_votes v WHERE v.comment_id = c.id AND v.user_id = ?), 0)
+        FROM comments c
+        WHERE c.id = ? AND c.deleted_at IS NULL
+    `, viewerID, commentID).Scan(
+		&comment.ID,
+		&comment.PostID,
+		&comment.ParentID,
+		&comment.Depth,
+		&comment.AuthorID,
+		&comment.Content,
+		&comment.CreatedAt,
+		&comment.EditedAt,
+		&comment.Hidden,
+		&comment.Pinned,
+		&comment.Score,
+		&comment.Accepted,
+		&comment.MyVote,
+	)
+	if err != nil {
+		return comment, err
+	}
+
+	comment.ContentHTML = render.Comment(comment.ID, comment.Content)
+
+	attachments, err := attachmentsFor(repo.db, "comment_id", []uint64{comment.ID})
+	if err != nil {
+		return comment, err
+	}
+	comment.Attachments = attachmentsOrEmpty(attachments[comment.ID])
+
+	return comment, nil
 }
 
 // Buscar autor do comentário
 func (repo CommentsRepository) GetAuthor(commentID uint64) (uint64, error) {
 	var authorID uint64
-	err := repo.db.QueryRow("SELECT author_id FROM comments WHERE id = ?", commentID).Scan(&authorID)
+	err := repo.db.QueryRow("SELECT author_id FROM comments WHERE id = ? AND deleted_at IS NULL", commentID).Scan(&authorID)
 	return authorID, err
 }
 
-// Listar comentários de um post
-func (repo CommentsRepository) ListComments(postID uint64) ([]model.CommentResponse, error) {
+// Listar comentários de um post (sem os de usuários bloqueados por quem consulta
+// ou que o bloquearam, nem os silenciados por ele)
+func (repo CommentsRepository) ListComments(viewerID, postID uint64) ([]model.CommentResponse, error) {
+	hidden, hiddenArgs := commentHiddenClause(viewerID)
+
 	rows, err := repo.db.Query(`
-        SELECT 
-            c.id, c.content, c.createdAt,
-            u.id, u.name, u.nick
+        SELECT `+commentResponseColumns+`
         FROM comments c
         JOIN users u ON u.id = c.author_id
-        WHERE c.post_id = ?
+        WHERE c.post_id = ? AND c.deleted_at IS NULL AND `+hidden+`
         ORDER BY c.createdAt ASC
-    `, postID)
+    `, append([]interface{}{postID}, hiddenArgs...)...)
 
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var comments []model.CommentResponse
-
-	for rows.Next() {
-		var c model.CommentResponse
-
-		err := rows.Scan(
-			&c.ID,
-			&c.Content,
-			&c.CreatedAt,
-			&c.Author.ID,
-			&c.Author.Name,
-			&c.Author.Nick,
-		)
-
-		if err != nil {
-			return nil, err
-		}
-
-		comments = append(comments, c)
-	}
-
-	return comments, nil
+	return scanCommentResponses(repo.db, viewerID, rows)
 }
 
-func (repo CommentsRepository) GetByCommentsPostID(postID uint64) ([]model.Comment, error) {
+// Comentários de um post visíveis para quem consulta (bloqueios escondem os dois
+// lados; contas e palavras silenciadas só para quem silenciou), com o fixado primeiro e,
+// em perguntas, a resposta aceita e as mais votadas em seguida
+func (repo CommentsRepository) GetByCommentsPostID(viewerID, postID uint64) ([]model.Comment, error) {
+	hidden, hiddenArgs := commentHiddenClause(viewerID)
+
 	rows, err := repo.db.Query(`
-        SELECT id, post_id, author_id, content, createdAt
-        FROM comments
-        WHERE post_id = ?
-        ORDER BY createdAt DESC
-    `, postID)
+        SELECT c.id, c.post_id, c.parent_id, c.depth, c.author_id, c.content, c.createdAt, c.edited_at,
+               c.hidden_at IS NOT NULL, c.pinned_at IS NOT NULL, c.score, c.accepted_at IS NOT NULL
+        FROM comments c
+        WHERE c.post_id = ? AND c.deleted_at IS NULL AND `+hidden+`
+        ORDER BY `+commentOrder+`, c.createdAt DESC
+    `, append([]interface{}{postID}, hiddenArgs...)...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	var comments []model.Comment
+	var commentIDs []uint64
 
 	for rows.Next() {
 		var comment model.Comment
 		if err := rows.Scan(
 			&comment.ID,
 			&comment.PostID,
+			&comment.ParentID,
+			&comment.Depth,
 			&comment.AuthorID,
 			&comment.Content,
 			&comment.CreatedAt,
+			&comment.EditedAt,
+			&comment.Hidden,
+			&comment.Pinned,
+			&comment.Score,
+			&comment.Accepted,
 		); err != nil {
 			return nil, err
 		}
+		comment.ContentHTML = render.Comment(comment.ID, comment.Content)
 		comments = append(comments, comment)
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attachments, err := attachmentsFor(repo.db, "comment_id", commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := commentVotes(repo.db, viewerID, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range comments {
+		comments[i].Attachments = attachmentsOrEmpty(attachments[comments[i].ID])
+		comments[i].MyVote = votes[comments[i].ID]
 	}
 
 	return comments, nil
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"api/src/avatar"
+	"api/src/model"
+	"api/src/render"
+	"database/sql"
+	"strings"
+)
+
+// Colunas lidas em todas as consultas de usuário, na ordem esperada por userFields.
+// As consultas devem apelidar a tabela users de "u".
+const userColumns = `u.id, u.name, u.nick, u.email, u.avatar_key, u.avatar_variants, u.createdAt,
+    u.bio, u.website, u.location, u.pronouns, u.github, u.gitlab, u.banner_key,
+    u.followers_count, u.following_count, u.posts_count, u.likes_received_count, u.is_private,
+    u.role, u.reputation`
+
+// userRow guarda as colunas que precisam de tratamento depois do Scan
+type userRow struct {
+	avatarKey      *string
+	avatarVariants *string
+	bannerKey      *string
+	private        bool
+}
+
+// userFields retorna os destinos do Scan correspondentes a userColumns
+func userFields(user *model.User, row *userRow) []interface{} {
+	return []interface{}{
+		&user.ID, &user.Name, &user.Nick, &user.Email, &row.avatarKey, &row.avatarVariants, &user.CreatedAt,
+		&user.Bio, &user.Website, &user.Location, &user.Pronouns, &user.GitHub, &user.GitLab, &row.bannerKey,
+		&user.Stats.Followers, &user.Stats.Following, &user.Stats.Posts, &user.Stats.LikesReceived, &row.private,
+		&user.Role, &user.Stats.Reputation,
+	}
+}
+
+// finish monta os campos derivados: endereços das imagens e a bio em HTML
+func (row userRow) finish(user *model.User) {
+	user.AvatarURL = avatar.URL(user.ID, row.avatarKey, row.avatarVariants)
+	user.BannerURL = avatar.BannerURL(row.bannerKey)
+	user.Private = &row.private
+	if user.Bio != nil {
+		user.BioHTML = render.Markdown(*user.Bio)
+	}
+}
+
+// scanUsers lê uma listagem de usuários e carrega a stack e as conquistas de cada um
+func scanUsers(db *sql.DB, rows *sql.Rows) ([]model.User, error) {
+	var users []model.User
+	var userIDs []uint64
+
+	for rows.Next() {
+		var user model.User
+		var row userRow
+		if err := rows.Scan(userFields(&user, &row)...); err != nil {
+			return nil, err
+		}
+		row.finish(&user)
+		users = append(users, user)
+		userIDs = append(userIDs, user.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stacks, err := techStacks(db, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	badges, err := userBadges(db, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		users[i].TechStack = stacks[users[i].ID]
+		users[i].Badges = badges[users[i].ID]
+	}
+
+	return users, nil
+}
+
+// techStacks busca as tecnologias dos usuários, na ordem escolhida por cada um
+func techStacks(db *sql.DB, userIDs []uint64) (map[uint64][]string, error) {
+	stacks := map[uint64][]string{}
+	if len(userIDs) == 0 {
+		return stacks, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	args := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		args[i] = id
+	}
+
+	rows, err := db.Query(`
+        SELECT user_id, tag FROM user_tech_stack
+        WHERE user_id IN (`+placeholders+`)
+        ORDER BY user_id, position
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID uint64
+		var tag string
+		if err := rows.Scan(&userID, &tag); err != nil {
+			return nil, err
+		}
+		stacks[userID] = append(stacks[userID], tag)
+	}
+
+	return stacks, rows.Err()
+}
+
+// replaceTechStack substitui a stack do usuário pela lista informada
+func replaceTechStack(tx *sql.Tx, userID uint64, tags []string) error {
+	if _, err := tx.Exec("DELETE FROM user_tech_stack WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+
+	for position, tag := range tags {
+		_, err := tx.Exec("INSERT INTO user_tech_stack (user_id, tag, position) VALUES (?, ?, ?)", userID, tag, position)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nullable grava strings vazias como NULL, para que o campo volte a ficar em branco
+func nullable(field *string) interface{} {
+	if field == nil || *field == "" {
+		return nil
+	}
+	return *field
+}
+
+// keepIfMissing mantém o valor atual dos campos que não vieram na atualização
+func keepIfMissing(update, current *model.Profile) {
+	fields := []struct{ update, current **string }{
+		{&update.Bio, &current.Bio},
+		{&update.Website, &current.Website},
+		{&update.Location, &current.Location},
+		{&update.Pronouns, &current.Pronouns},
+		{&update.GitHub, &current.GitHub},
+		{&update.GitLab, &current.GitLab},
+	}
+
+	for _, field := range fields {
+		if *field.update == nil {
+			*field.update = *field.current
+		}
+	}
+
+	if update.Private == nil {
+		update.Private = current.Private
+	}
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"api/src/model"
+	"database/sql"
+	"errors"
+)
+
+var ErrNotAnAnswer = errors.New("apenas respostas diretas a uma pergunta podem ser votadas ou aceitas")
+
+// answeredExpr indica se a pergunta (posts apelidada de "p") tem uma resposta aceita ativa
+const answeredExpr = `EXISTS(
+    SELECT 1 FROM comments ac
+    WHERE ac.post_id = p.id AND ac.accepted_at IS NOT NULL AND ac.deleted_at IS NULL
+)`
+
+const answeredColumn = answeredExpr + ` AS answered`
+
+// Busca o autor da pergunta de uma resposta ativa. Respostas são os comentários de
+// primeiro nível de posts do tipo pergunta; os demais comentários retornam ErrNotAnAnswer.
+func (repo CommentsRepository) GetAnswerInfo(commentID uint64) (answerAuthorID, askerID uint64, err error) {
+	var isAnswer bool
+
+	err = repo.db.QueryRow(`
+        SELECT c.author_id, p.author_id, p.post_type = 'question' AND c.parent_id IS NULL
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.id = ? AND c.deleted_at IS NULL
+    `, commentID).Scan(&answerAuthorID, &askerID, &isAnswer)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isAnswer {
+		return 0, 0, ErrNotAnAnswer
+	}
+
+	return answerAuthorID, askerID, nil
+}
+
+// Registra o voto (+1 ou -1) do usuário na resposta, substituindo o anterior,
+// e atualiza o saldo da resposta
+func (repo CommentsRepository) Vote(userID, commentID uint64, value int) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := currentVote(tx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	if previous == value {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO comment_votes (comment_id, user_id, value) VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE value = VALUES(value)
+    `, commentID, userID, value)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE comments SET score = score + ? WHERE id = ?", value-previous, commentID); err != nil {
+		return err
+	}
+
+	if err := revokeVoteReputation(tx, userID, commentID); err != nil {
+		return err
+	}
+
+	event := model.ReputationAnswerUpvoted
+	if value < 0 {
+		event = model.ReputationAnswerDownvoted
+	}
+	if err := grantReputation(tx, event, reputationKey(commentID, userID), userID, commentReputationSource, commentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Remove o voto do usuário na resposta e desfaz seu efeito no saldo
+func (repo CommentsRepository) Unvote(userID, commentID uint64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	previous, err := currentVote(tx, userID, commentID)
+	if err != nil {
+		return err
+	}
+	if previous == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec("DELETE FROM comment_votes WHERE comment_id = ? AND user_id = ?", commentID, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("UPDATE comments SET score = score - ? WHERE id = ?", previous, commentID); err != nil {
+		return err
+	}
+
+	if err := revokeVoteReputation(tx, userID, commentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// revokeVoteReputation desfaz os pontos do voto atual do usuário na resposta
+func revokeVoteReputation(ex execer, userID, commentID uint64) error {
+	key := reputationKey(commentID, userID)
+	if err := revokeReputation(ex, model.ReputationAnswerUpvoted, key); err != nil {
+		return err
+	}
+	return revokeReputation(ex, model.ReputationAnswerDownvoted, key)
+}
+
+// currentVote lê, travando a linha, o voto atual do usuário na resposta (0 se não votou)
+func currentVote(tx *sql.Tx, userID, commentID uint64) (int, error) {
+	var value int
+	err := tx.QueryRow(
+		"SELECT value FROM comment_votes WHERE comment_id = ? AND user_id = ? FOR UPDATE",
+		commentID, userID,
+	).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return value, err
+}
+
+// Marca a resposta como aceita, substituindo a aceita anteriormente na mesma pergunta.
+// Respostas ocultas não podem ser aceitas (sql.ErrNoRows).
+func (repo CommentsRepository) Accept(commentID uint64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var postID, askerID uint64
+	err = tx.QueryRow(`
+        SELECT c.post_id, p.author_id
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.id = ? AND c.deleted_at IS NULL AND c.hidden_at IS NULL
+        FOR UPDATE
+    `, commentID).Scan(&postID, &askerID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE comments SET accepted_at = NULL WHERE post_id = ? AND accepted_at IS NOT NULL AND id <> ?", postID, commentID)
+	if err != nil {
+		return err
+	}
+
+	err = releaseReputation(tx, "event = ? AND post_id = ? AND comment_id <> ?", model.ReputationAnswerAccepted, postID, commentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE comments SET accepted_at = COALESCE(accepted_at, CURRENT_TIMESTAMP) WHERE id = ?", commentID)
+	if err != nil {
+		return err
+	}
+
+	err = grantReputation(tx, model.ReputationAnswerAccepted, reputationKey(commentID), askerID, commentReputationSource, commentID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Desmarca a resposta aceita
+func (repo CommentsRepository) Unaccept(commentID uint64) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = expectAffected(tx.Exec(
+		"UPDATE comments SET accepted_at = NULL WHERE id = ? AND accepted_at IS NOT NULL",
+		commentID,
+	))
+	if err != nil {
+		return err
+	}
+
+	if err := revokeReputation(tx, model.ReputationAnswerAccepted, reputationKey(commentID)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// commentVotes busca os votos de quem consulta nos comentários informados
+func commentVotes(db *sql.DB, viewerID uint64, commentIDs []uint64) (map[uint64]int, error) {
+	votes := map[uint64]int{}
+	if len(commentIDs) == 0 {
+		return votes, nil
+	}
+
+	placeholders, args := idPlaceholders(commentIDs)
+
+	rows, err := db.Query(
+		"SELECT comment_id, value FROM comment_votes WHERE user_id = ? AND comment_id IN ("+placeholders+")",
+		append([]interface{}{viewerID}, args...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID uint64
+		var value int
+		if err := rows.Scan(&commentID, &value); err != nil {
+			return nil, err
+		}
+		votes[commentID] = value
+	}
+
+	return votes, rows.Err()
+}
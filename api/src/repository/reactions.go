@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"api/src/model"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// Adiciona uma reação do usuário ao comentário; repetir a mesma reação não tem efeito
+func (repo CommentsRepository) React(userID, commentID uint64, emoji string) error {
+	_, err := repo.db.Exec(
+		"INSERT IGNORE INTO comment_reactions (comment_id, user_id, emoji) VALUES (?, ?, ?)",
+		commentID, userID, emoji,
+	)
+	return err
+}
+
+// Remove uma reação do usuário ao comentário
+func (repo CommentsRepository) Unreact(userID, commentID uint64, emoji string) error {
+	return expectAffected(repo.db.Exec(
+		"DELETE FROM comment_reactions WHERE comment_id = ? AND user_id = ? AND emoji = ?",
+		commentID, userID, emoji,
+	))
+}
+
+// Reage ao post. Cada usuário tem uma reação por post: reagir de novo troca o emoji.
+func (r PostsRepository) React(userID, postID uint64, emoji string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        INSERT INTO likes (user_id, post_id, reaction) VALUES (?, ?, ?)
+        ON DUPLICATE KEY UPDATE reaction = VALUES(reaction)
+    `, userID, postID, emoji)
+	if err != nil {
+		return err
+	}
+
+	// 1 linha afetada é uma reação nova; 2, a troca de emoji, que não muda o total
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if inserted == 1 {
+		if err := adjustLikeCounter(tx, postID, 1); err != nil {
+			return err
+		}
+
+		if err := grantReputation(tx, model.ReputationPostLiked, reputationKey(postID, userID), userID, postReputationSource, postID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Totais por emoji e reação de quem consulta em um post
+func (r PostsRepository) GetReactionSummary(viewerID, postID uint64) (model.PostReactionSummary, error) {
+	summaries, err := postReactionSummaries(r.db, viewerID, []uint64{postID})
+	if err != nil {
+		return model.PostReactionSummary{}, err
+	}
+	return summaries[postID], nil
+}
+
+// Lista quem reagiu ao post, da reação mais recente para a mais antiga, opcionalmente
+// de um único emoji. Usuários com bloqueio em relação a quem consulta ficam de fora.
+func (r PostsRepository) GetReactions(viewerID, postID uint64, emoji string, limit, offset int) ([]model.Reaction, error) {
+	notBlocked, blockArgs := notBlockedClause("u.id", viewerID)
+
+	args := append([]interface{}{postID, emoji, emoji}, blockArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(`
+        SELECT `+userColumns+`, l.reaction, l.createdAt
+        FROM likes l
+        JOIN users u ON u.id = l.user_id
+        WHERE l.post_id = ? AND (? = '' OR l.reaction = ?) AND `+notBlocked+`
+        ORDER BY l.createdAt DESC, u.id DESC
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReactions(rows)
+}
+
+// Totais por emoji e reações de quem consulta em um comentário
+func (repo CommentsRepository) GetReactionSummary(viewerID, commentID uint64) (model.ReactionSummary, error) {
+	summaries, err := reactionSummaries(repo.db, viewerID, []uint64{commentID})
+	if err != nil {
+		return model.ReactionSummary{}, err
+	}
+	return summaries[commentID], nil
+}
+
+// Lista quem reagiu ao comentário, da reação mais recente para a mais antiga, opcionalmente
+// de um único emoji. Usuários com bloqueio em relação a quem consulta ficam de fora.
+func (repo CommentsRepository) GetReactions(viewerID, commentID uint64, emoji string, limit, offset int) ([]model.Reaction, error) {
+	notBlocked, blockArgs := notBlockedClause("u.id", viewerID)
+
+	args := append([]interface{}{commentID, emoji, emoji}, blockArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := repo.db.Query(`
+        SELECT `+userColumns+`, cr.emoji, cr.createdAt
+        FROM comment_reactions cr
+        JOIN users u ON u.id = cr.user_id
+        WHERE cr.comment_id = ? AND (? = '' OR cr.emoji = ?) AND `+notBlocked+`
+        ORDER BY cr.createdAt DESC, u.id DESC
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanReactions(rows)
+}
+
+// scanReactions lê as colunas de userColumns seguidas do emoji e da data da reação
+func scanReactions(rows *sql.Rows) ([]model.Reaction, error) {
+	reactions := []model.Reaction{}
+	for rows.Next() {
+		var user model.User
+		var row userRow
+		var emoji string
+		var createdAt time.Time
+
+		if err := rows.Scan(append(userFields(&user, &row), &emoji, &createdAt)...); err != nil {
+			return nil, err
+		}
+		row.finish(&user)
+
+		reactions = append(reactions, model.Reaction{User: user.Public(), Emoji: emoji, CreatedAt: createdAt})
+	}
+
+	return reactions, rows.Err()
+}
+
+// reactionSummaries agrega as reações dos comentários informados: os totais por emoji
+// e as reações de quem consulta
+func reactionSummaries(db *sql.DB, viewerID uint64, commentIDs []uint64) (map[uint64]model.ReactionSummary, error) {
+	summaries := map[uint64]model.ReactionSummary{}
+	if len(commentIDs) == 0 {
+		return summaries, nil
+	}
+
+	counts, err := reactionCounts(db, viewerID, "comment_reactions", "comment_id", "emoji", commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range commentIDs {
+		summaries[id] = model.ReactionSummary{Reactions: counts[id], MyReactions: []string{}}
+	}
+
+	placeholders, args := idPlaceholders(commentIDs)
+
+	rows, err := db.Query(`
+        SELECT comment_id, emoji
+        FROM comment_reactions
+        WHERE user_id = ? AND comment_id IN (`+placeholders+`)
+        ORDER BY createdAt ASC
+    `, append([]interface{}{viewerID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var commentID uint64
+		var emoji string
+		if err := rows.Scan(&commentID, &emoji); err != nil {
+			return nil, err
+		}
+		summary := summaries[commentID]
+		summary.MyReactions = append(summary.MyReactions, emoji)
+		summaries[commentID] = summary
+	}
+
+	return summaries, rows.Err()
+}
+
+// postReactionSummaries agrega as reações dos posts informados: os totais por emoji
+// e a reação de quem consulta
+func postReactionSummaries(db *sql.DB, viewerID uint64, postIDs []uint64) (map[uint64]model.PostReactionSummary, error) {
+	summaries := map[uint64]model.PostReactionSummary{}
+	if len(postIDs) == 0 {
+		return summaries, nil
+	}
+
+	counts, err := reactionCounts(db, viewerID, "likes", "post_id", "reaction", postIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range postIDs {
+		summaries[id] = model.PostReactionSummary{Reactions: counts[id]}
+	}
+
+	placeholders, args := idPlaceholders(postIDs)
+
+	rows, err := db.Query(
+		"SELECT post_id, reaction FROM likes WHERE user_id = ? AND post_id IN ("+placeholders+")",
+		append([]interface{}{viewerID}, args...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID uint64
+		var reaction string
+		if err := rows.Scan(&postID, &reaction); err != nil {
+			return nil, err
+		}
+		summary := summaries[postID]
+		summary.MyReaction = &reaction
+		summaries[postID] = summary
+	}
+
+	return summaries, rows.Err()
+}
+
+// reactionCounts conta as reações por emoji, do mais usado para o menos usado (empates
+// pela reação mais antiga). Todo ID informado recebe uma lista, mesmo vazia. Reações
+// de usuários com bloqueio em relação a quem consulta não contam, assim como ficam
+// fora da lista de quem reagiu.
+func reactionCounts(db *sql.DB, viewerID uint64, table, idColumn, emojiColumn string, ids []uint64) (map[uint64][]model.ReactionCount, error) {
+	counts := map[uint64][]model.ReactionCount{}
+	for _, id := range ids {
+		counts[id] = []model.ReactionCount{}
+	}
+
+	placeholders, args := idPlaceholders(ids)
+	notBlocked, blockArgs := notBlockedClause("t.user_id", viewerID)
+
+	rows, err := db.Query(`
+        SELECT t.`+idColumn+`, t.`+emojiColumn+`, COUNT(*)
+        FROM `+table+` t
+        WHERE t.`+idColumn+` IN (`+placeholders+`) AND `+notBlocked+`
+        GROUP BY t.`+idColumn+`, t.`+emojiColumn+`
+        ORDER BY t.`+idColumn+`, COUNT(*) DESC, MIN(t.createdAt) ASC
+    `, append(args, blockArgs...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint64
+		var count model.ReactionCount
+		if err := rows.Scan(&id, &count.Emoji, &count.Count); err != nil {
+			return nil, err
+		}
+		counts[id] = append(counts[id], count)
+	}
+
+	return counts, rows.Err()
+}
+
+// idPlaceholders monta os "?" de uma cláusula IN e seus argumentos
+func idPlaceholders(ids []uint64) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", len(ids)), ","), args
+}
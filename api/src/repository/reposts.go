@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"api/src/avatar"
+	"api/src/model"
+	"api/src/render"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var ErrNotShareable = errors.New("apenas posts públicos de contas públicas podem ser repostados ou citados")
+
+// shareableClause limita os posts (apelidados de "p") aos que podem ser repostados ou
+// citados: públicos e de contas públicas, para que o compartilhamento não os leve além
+// do público escolhido pelo autor. Nos feeds, o post original continua sujeito às
+// regras de visibilidade de quem consulta.
+const shareableClause = `(
+    p.visibility = 'public'
+    AND NOT EXISTS(SELECT 1 FROM users sa WHERE sa.id = p.author_id AND sa.is_private)
+)`
+
+// Verifica se o usuário pode repostar ou citar o post: sql.ErrNoRows se ele não vê o
+// post, ErrNotShareable se o post não pode ser compartilhado
+func (r PostsRepository) CheckShareable(userID, postID uint64) error {
+	visible, visibleArgs := postVisibleClause(userID)
+
+	var shareable bool
+	err := r.db.QueryRow(
+		"SELECT "+shareableClause+" FROM posts p WHERE p.id = ? AND "+visible,
+		append([]interface{}{postID}, visibleArgs...)...,
+	).Scan(&shareable)
+	if err != nil {
+		return err
+	}
+	if !shareable {
+		return ErrNotShareable
+	}
+
+	return nil
+}
+
+// Reposta o post (sql.ErrNoRows se o usuário já o repostou)
+func (r PostsRepository) Repost(userID, postID uint64) error {
+	return expectAffected(r.db.Exec("INSERT IGNORE INTO reposts (user_id, post_id) VALUES (?, ?)", userID, postID))
+}
+
+// Desfaz o repost (sql.ErrNoRows se o usuário não tinha repostado)
+func (r PostsRepository) Unrepost(userID, postID uint64) error {
+	return expectAffected(r.db.Exec("DELETE FROM reposts WHERE user_id = ? AND post_id = ?", userID, postID))
+}
+
+// Totais de reposts e de citações publicadas do post
+func (r PostsRepository) CountShares(postID uint64) (reposts, quotes uint64, err error) {
+	err = r.db.QueryRow(`
+        SELECT
+            (SELECT COUNT(*) FROM reposts WHERE post_id = ?),
+            (SELECT COUNT(*) FROM posts WHERE quoted_post_id = ? AND status = 'published' AND deleted_at IS NULL)
+    `, postID, postID).Scan(&reposts, &quotes)
+	return reposts, quotes, err
+}
+
+// Lista quem repostou o post, do repost mais recente para o mais antigo.
+// Usuários com bloqueio em relação a quem consulta ficam de fora.
+func (r PostsRepository) GetReposts(viewerID, postID uint64, limit, offset int) ([]model.Repost, error) {
+	notBlocked, blockArgs := notBlockedClause("u.id", viewerID)
+
+	args := append([]interface{}{postID}, blockArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(`
+        SELECT `+userColumns+`, rp.createdAt
+        FROM reposts rp
+        JOIN users u ON u.id = rp.user_id
+        WHERE rp.post_id = ? AND `+notBlocked+`
+        ORDER BY rp.createdAt DESC, u.id
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reposts := []model.Repost{}
+	for rows.Next() {
+		var user model.User
+		var row userRow
+		var createdAt time.Time
+
+		if err := rows.Scan(append(userFields(&user, &row), &createdAt)...); err != nil {
+			return nil, err
+		}
+		row.finish(&user)
+
+		reposts = append(reposts, model.Repost{User: user.Public(), CreatedAt: createdAt})
+	}
+
+	return reposts, rows.Err()
+}
+
+// Lista as citações do post que aparecem no feed de quem consulta, da mais recente
+// para a mais antiga, cada uma com o post citado embutido
+func (r PostsRepository) GetQuotes(viewerID, postID uint64, limit, offset int) ([]model.Post, error) {
+	feed, feedArgs := postFeedClause(viewerID)
+
+	args := append([]interface{}{postID}, feedArgs...)
+	args = append(args, limit, offset)
+
+	quoted, err := quotedPosts(r.db, viewerID, []uint64{postID})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+        SELECT p.id, p.title, p.content, p.author_id, u.nick, p.visibility, p.post_type, p.edited_at, p.createdAt
+        FROM posts p
+        JOIN users u ON u.id = p.author_id
+        WHERE p.quoted_post_id = ? AND `+feed+`
+        ORDER BY p.createdAt DESC, p.id DESC
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotes := []model.Post{}
+	for rows.Next() {
+		var post model.Post
+		if err := rows.Scan(
+			&post.ID,
+			&post.Title,
+			&post.Content,
+			&post.AuthorID,
+			&post.AuthorNickname,
+			&post.Visibility,
+			&post.Type,
+			&post.EditedAt,
+			&post.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		post.ContentHTML = render.Post(post.ID, post.Content)
+		post.QuotedPostID = &postID
+		post.QuotedPost = quoted[postID]
+
+		quotes = append(quotes, post)
+	}
+
+	return quotes, rows.Err()
+}
+
+// quotedPosts busca os posts citados que quem consulta pode ver; os demais ficam
+// fora do mapa e a citação é exibida sem o post embutido
+func quotedPosts(db *sql.DB, viewerID uint64, postIDs []uint64) (map[uint64]*model.QuotedPost, error) {
+	quoted := map[uint64]*model.QuotedPost{}
+	if len(postIDs) == 0 {
+		return quoted, nil
+	}
+
+	visible, visibleArgs := postVisibleClause(viewerID)
+	placeholders, args := idPlaceholders(postIDs)
+
+	rows, err := db.Query(`
+        SELECT p.id, p.title, p.content, p.author_id, u.nick, u.avatar_key, u.avatar_variants, p.createdAt
+        FROM posts p
+        JOIN users u ON u.id = p.author_id
+        WHERE p.id IN (`+placeholders+`) AND `+visible,
+		append(args, visibleArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var post model.QuotedPost
+		var content string
+		var avatarKey, avatarVariants *string
+
+		if err := rows.Scan(&post.ID, &post.Title, &content, &post.AuthorID, &post.AuthorNickname, &avatarKey, &avatarVariants, &post.CreatedAt); err != nil {
+			return nil, err
+		}
+		post.ContentHTML = render.Post(post.ID, content)
+		post.AuthorPhotoURL = avatar.URL(post.AuthorID, avatarKey, avatarVariants)
+
+		quoted[post.ID] = &post
+	}
+
+	return quoted, rows.Err()
+}
+
+// quotedIDs reúne os posts citados, ignorando os posts que não citam nenhum
+func quotedIDs(ids []*uint64) []uint64 {
+	var quoted []uint64
+	for _, id := range ids {
+		if id != nil {
+			quoted = append(quoted, *id)
+		}
+	}
+	return quoted
+}
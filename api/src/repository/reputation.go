@@ -0,0 +1,239 @@
+package repository
+
+// Reputação: cada evento que dá (ou tira) pontos de um usuário vira uma linha em
+// reputation_events, e users.reputation guarda a soma, atualizada na mesma transação
+// que registra a curtida, o voto, o follow ou a moderação que originou o evento.
+// Desfazer a ação apaga o evento e desconta exatamente os pontos gravados nele.
+//
+// Ninguém ganha ou perde pontos por ações sobre o próprio conteúdo. Recompute refaz
+// tudo a partir das tabelas de origem, aplicando as regras atuais.
+
+import (
+	"api/src/config"
+	"api/src/model"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ReputationRepository acessa o histórico de reputação
+type ReputationRepository struct {
+	db *sql.DB
+}
+
+// Cria um novo repositório de reputação
+func NewReputationRepository(db *sql.DB) *ReputationRepository {
+	return &ReputationRepository{db}
+}
+
+// Origens dos eventos: consultas que devolvem quem recebe os pontos e o post e o
+// comentário relacionados
+const (
+	postReputationSource    = "SELECT author_id AS user_id, id AS post_id, NULL AS comment_id FROM posts WHERE id = ?"
+	commentReputationSource = "SELECT author_id AS user_id, post_id, id AS comment_id FROM comments WHERE id = ?"
+	userReputationSource    = "SELECT ? AS user_id, NULL AS post_id, NULL AS comment_id"
+)
+
+// reputationKey monta a chave de origem de um evento a partir dos IDs envolvidos
+func reputationKey(ids ...uint64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ":")
+}
+
+// grantReputation registra o evento para o usuário devolvido por source e soma os
+// pontos à reputação dele. Eventos já registrados, sem pontos nas regras ou em que
+// o usuário é o próprio autor da ação são ignorados.
+func grantReputation(ex execer, event, key string, actorID uint64, source string, args ...interface{}) error {
+	points := config.ReputationRules[event]
+	if points == 0 {
+		return nil
+	}
+
+	result, err := ex.Exec(`
+        INSERT IGNORE INTO reputation_events (user_id, post_id, comment_id, event, points, source_key, actor_id)
+        SELECT src.user_id, src.post_id, src.comment_id, ?, ?, ?, ?
+        FROM (`+source+`) src
+        WHERE src.user_id <> ?
+    `, append(append([]interface{}{event, points, key, actorID}, args...), actorID)...)
+	if err != nil {
+		return err
+	}
+
+	if inserted, err := result.RowsAffected(); err != nil || inserted == 0 {
+		return err
+	}
+
+	_, err = ex.Exec(`
+        UPDATE users
+        SET reputation = reputation + ?
+        WHERE id = (SELECT user_id FROM reputation_events WHERE event = ? AND source_key = ?)
+    `, points, event, key)
+	return err
+}
+
+// revokeReputation desfaz um evento registrado por grantReputation
+func revokeReputation(ex execer, event, key string) error {
+	return releaseReputation(ex, "event = ? AND source_key = ?", event, key)
+}
+
+// releaseReputation apaga os eventos que atendem à condição e desconta os pontos de
+// quem os recebeu. Usado também antes de apagar de fato usuários, posts e comentários,
+// já que os eventos relacionados somem em cascata.
+func releaseReputation(ex execer, condition string, args ...interface{}) error {
+	_, err := ex.Exec(`
+        UPDATE users u
+        JOIN (
+            SELECT user_id, SUM(points) AS total
+            FROM reputation_events
+            WHERE `+condition+`
+            GROUP BY user_id
+        ) released ON released.user_id = u.id
+        SET u.reputation = u.reputation - released.total
+    `, args...)
+	if err != nil {
+		return err
+	}
+
+	_, err = ex.Exec("DELETE FROM reputation_events WHERE "+condition, args...)
+	return err
+}
+
+// Lista o histórico de reputação do usuário, do evento mais recente para o mais antigo
+func (r ReputationRepository) GetHistory(userID uint64, limit, offset int) ([]model.ReputationEvent, error) {
+	rows, err := r.db.Query(`
+        SELECT id, event, points, post_id, comment_id, actor_id, createdAt
+        FROM reputation_events
+        WHERE user_id = ?
+        ORDER BY createdAt DESC, id DESC
+        LIMIT ? OFFSET ?
+    `, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.ReputationEvent{}
+	for rows.Next() {
+		var event model.ReputationEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.Event,
+			&event.Points,
+			&event.PostID,
+			&event.CommentID,
+			&event.ActorID,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// Eventos reconstruídos por Recompute. Cada consulta devolve quem recebe os pontos,
+// post e comentário relacionados, chave de origem (no mesmo formato usado ao registrar
+// o evento), autor da ação e data.
+var reputationSources = []struct {
+	event string
+	query string
+}{
+	{model.ReputationPostLiked, `
+        SELECT p.author_id AS user_id, p.id AS post_id, NULL AS comment_id,
+               CONCAT(l.post_id, ':', l.user_id) AS source_key, l.user_id AS actor_id, l.createdAt
+        FROM likes l
+        JOIN posts p ON p.id = l.post_id`},
+	{model.ReputationAnswerUpvoted, `
+        SELECT c.author_id AS user_id, c.post_id, c.id AS comment_id,
+               CONCAT(v.comment_id, ':', v.user_id) AS source_key, v.user_id AS actor_id, v.createdAt
+        FROM comment_votes v
+        JOIN comments c ON c.id = v.comment_id
+        WHERE v.value > 0`},
+	{model.ReputationAnswerDownvoted, `
+        SELECT c.author_id AS user_id, c.post_id, c.id AS comment_id,
+               CONCAT(v.comment_id, ':', v.user_id) AS source_key, v.user_id AS actor_id, v.createdAt
+        FROM comment_votes v
+        JOIN comments c ON c.id = v.comment_id
+        WHERE v.value < 0`},
+	{model.ReputationAnswerAccepted, `
+        SELECT c.author_id AS user_id, c.post_id, c.id AS comment_id,
+               CONCAT(c.id) AS source_key, p.author_id AS actor_id, c.accepted_at AS createdAt
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.accepted_at IS NOT NULL`},
+	{model.ReputationFollowerGained, `
+        SELECT f.following_id AS user_id, NULL AS post_id, NULL AS comment_id,
+               CONCAT(f.follower_id, ':', f.following_id) AS source_key, f.follower_id AS actor_id,
+               f.createdAt
+        FROM followers f`},
+	{model.ReputationCommentHidden, `
+        SELECT c.author_id AS user_id, c.post_id, c.id AS comment_id,
+               CONCAT(c.id) AS source_key, p.author_id AS actor_id, c.hidden_at AS createdAt
+        FROM comments c
+        JOIN posts p ON p.id = c.post_id
+        WHERE c.hidden_at IS NOT NULL`},
+	{model.ReputationCommentRemoved, `
+        SELECT c.author_id AS user_id, c.post_id, c.id AS comment_id,
+               CONCAT(c.id) AS source_key, c.deleted_by AS actor_id, c.deleted_at AS createdAt
+        FROM comments c
+        WHERE c.deleted_by IS NOT NULL`},
+}
+
+// Refaz o histórico e a reputação de todos os usuários a partir das curtidas, votos,
+// respostas aceitas, seguidores e moderação existentes, com as regras atuais.
+// Retorna quantos eventos foram registrados.
+func (r ReputationRepository) Recompute() (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM reputation_events"); err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, source := range reputationSources {
+		points := config.ReputationRules[source.event]
+		if points == 0 {
+			continue
+		}
+
+		result, err := tx.Exec(`
+            INSERT INTO reputation_events (user_id, post_id, comment_id, event, points, source_key, actor_id, createdAt)
+            SELECT src.user_id, src.post_id, src.comment_id, ?, ?, src.source_key, src.actor_id,
+                   COALESCE(src.createdAt, CURRENT_TIMESTAMP)
+            FROM (`+source.query+`) src
+            WHERE src.user_id <> src.actor_id
+        `, source.event, points)
+		if err != nil {
+			return 0, fmt.Errorf("erro ao recalcular %s: %w", source.event, err)
+		}
+
+		inserted, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += inserted
+	}
+
+	_, err = tx.Exec(`
+        UPDATE users u
+        LEFT JOIN (
+            SELECT user_id, SUM(points) AS total
+            FROM reputation_events
+            GROUP BY user_id
+        ) earned ON earned.user_id = u.id
+        SET u.reputation = COALESCE(earned.total, 0)
+    `)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, tx.Commit()
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"api/src/model"
+)
+
+// Lista as revisões de um post, da mais recente para a mais antiga (sem o conteúdo)
+func (r PostsRepository) GetRevisions(postID uint64) ([]model.Revision, error) {
+	rows, err := r.db.Query(`
+        SELECT pr.id, pr.post_id, pr.editor_id, u.nick, pr.title, pr.createdAt
+        FROM post_revisions pr
+        LEFT JOIN users u ON u.id = pr.editor_id
+        WHERE pr.post_id = ?
+        ORDER BY pr.id DESC
+    `, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.Revision{}
+
+	for rows.Next() {
+		var revision model.Revision
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.PostID,
+			&revision.EditorID,
+			&revision.EditorNickname,
+			&revision.Title,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// Busca uma revisão específica de um post
+func (r PostsRepository) GetRevision(postID, revisionID uint64) (model.Revision, error) {
+	var revision model.Revision
+
+	err := r.db.QueryRow(`
+        SELECT pr.id, pr.post_id, pr.editor_id, u.nick, pr.title, pr.content, pr.createdAt
+        FROM post_revisions pr
+        LEFT JOIN users u ON u.id = pr.editor_id
+        WHERE pr.post_id = ? AND pr.id = ?
+    `, postID, revisionID).Scan(
+		&revision.ID,
+		&revision.PostID,
+		&revision.EditorID,
+		&revision.EditorNickname,
+		&revision.Title,
+		&revision.Content,
+		&revision.CreatedAt,
+	)
+
+	return revision, err
+}
+
+// Lista o histórico de edições de um comentário, da mais recente para a mais antiga
+func (repo CommentsRepository) GetHistory(commentID uint64) ([]model.CommentRevision, error) {
+	rows, err := repo.db.Query(`
+        SELECT cr.id, cr.comment_id, cr.editor_id, u.nick, cr.content, cr.createdAt
+        FROM comment_revisions cr
+        JOIN users u ON u.id = cr.editor_id
+        WHERE cr.comment_id = ?
+        ORDER BY cr.id DESC
+    `, commentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []model.CommentRevision{}
+
+	for rows.Next() {
+		var revision model.CommentRevision
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.CommentID,
+			&revision.EditorID,
+			&revision.EditorNickname,
+			&revision.Content,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
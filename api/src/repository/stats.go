@@ -0,0 +1,84 @@
+package repository
+
+// Contadores do perfil mantidos na tabela users, atualizados na mesma transação que
+// altera seguidores, posts e curtidas. Assim o perfil não precisa contar linhas a
+// cada requisição.
+//
+// Só entram na contagem posts publicados fora da lixeira, e as curtidas recebidas
+// nesses posts.
+
+import "database/sql"
+
+// execer é satisfeito tanto por *sql.DB quanto por *sql.Tx
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// adjustFollowCounters atualiza os dois lados de um follow (delta +1 ou -1)
+func adjustFollowCounters(ex execer, followerID, followingID uint64, delta int) error {
+	_, err := ex.Exec(`
+        UPDATE users
+        SET following_count = GREATEST(following_count + IF(id = ?, ?, 0), 0),
+            followers_count = GREATEST(followers_count + IF(id = ?, ?, 0), 0)
+        WHERE id IN (?, ?)
+    `, followerID, delta, followingID, delta, followerID, followingID)
+	return err
+}
+
+// adjustPostCounters soma delta ao total de posts do autor e, junto, as curtidas do
+// post ao total recebido. Não faz nada se o post não estiver publicado.
+func adjustPostCounters(ex execer, postID uint64, delta int) error {
+	_, err := ex.Exec(`
+        UPDATE users u
+        JOIN posts p ON p.author_id = u.id
+        SET u.posts_count = GREATEST(u.posts_count + ?, 0),
+            u.likes_received_count = GREATEST(
+                u.likes_received_count + ? * (SELECT COUNT(*) FROM likes WHERE post_id = p.id), 0
+            )
+        WHERE p.id = ? AND p.status = 'published'
+    `, delta, delta, postID)
+	return err
+}
+
+// adjustLikeCounter soma delta às curtidas recebidas pelo autor do post
+func adjustLikeCounter(ex execer, postID uint64, delta int) error {
+	_, err := ex.Exec(`
+        UPDATE users u
+        JOIN posts p ON p.author_id = u.id
+        SET u.likes_received_count = GREATEST(u.likes_received_count + ?, 0)
+        WHERE p.id = ? AND p.status = 'published' AND p.deleted_at IS NULL
+    `, delta, postID)
+	return err
+}
+
+// releaseUserCounters desfaz a participação de um usuário nos contadores dos
+// outros antes que ele seja excluído (as linhas somem em cascata, sem ajustes)
+func releaseUserCounters(ex execer, userID uint64) error {
+	statements := []string{
+		`UPDATE users u
+        JOIN followers f ON f.following_id = u.id
+        SET u.followers_count = GREATEST(u.followers_count - 1, 0)
+        WHERE f.follower_id = ?`,
+		`UPDATE users u
+        JOIN followers f ON f.follower_id = u.id
+        SET u.following_count = GREATEST(u.following_count - 1, 0)
+        WHERE f.following_id = ?`,
+		`UPDATE users u
+        JOIN (
+            SELECT p.author_id, COUNT(*) AS total
+            FROM likes l
+            JOIN posts p ON p.id = l.post_id
+            WHERE l.user_id = ? AND p.status = 'published' AND p.deleted_at IS NULL
+            GROUP BY p.author_id
+        ) received ON received.author_id = u.id
+        SET u.likes_received_count = GREATEST(u.likes_received_count - received.total, 0)`,
+	}
+
+	for _, statement := range statements {
+		if _, err := ex.Exec(statement, userID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"api/src/avatar"
+	"api/src/model"
+	"api/src/render"
+	"database/sql"
+)
+
+// liveReply filtra as respostas (apelidadas de "r") que ainda contam: não removidas ou,
+// removidas, com respostas próprias
+const liveReply = `(r.deleted_at IS NULL OR EXISTS(SELECT 1 FROM comments rr WHERE rr.parent_id = r.id))`
+
+// Colunas lidas por scanCommentResponses (comments apelidada de "c", users de "u").
+// O autor é lido com LEFT JOIN: marcadores de usuários excluídos não têm autor.
+const commentResponseColumns = `c.id, c.parent_id, c.depth, c.content, c.createdAt, c.edited_at, c.deleted_at IS NOT NULL,
+    c.hidden_at IS NOT NULL, c.pinned_at IS NOT NULL, c.score, c.accepted_at IS NOT NULL,
+    COALESCE(u.id, 0), COALESCE(u.name, ''), COALESCE(u.nick, ''), u.avatar_key, u.avatar_variants,
+    (SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id AND ` + liveReply + `) AS reply_count`
+
+// commentOrder ordena o comentário fixado primeiro e, nas perguntas, a resposta aceita
+// e depois as mais votadas (em posts comuns o saldo é sempre zero)
+const commentOrder = `c.pinned_at IS NULL, c.accepted_at IS NULL, c.score DESC`
+
+// threadClause mantém os comentários visíveis para quem consulta e, dos removidos,
+// apenas os que têm respostas que ainda contam (as mesmas de reply_count), que
+// aparecem como "[removed]" para não quebrar a conversa
+func threadClause(viewerID uint64) (string, []interface{}) {
+	hidden, args := commentHiddenClause(viewerID)
+
+	clause := `(
+        (c.deleted_at IS NULL AND ` + hidden + `)
+        OR (c.deleted_at IS NOT NULL AND EXISTS(SELECT 1 FROM comments r WHERE r.parent_id = c.id AND ` + liveReply + `))
+    )`
+
+	return clause, args
+}
+
+// Comentários de primeiro nível de um post, com a contagem de respostas, paginados.
+// O comentário fixado pelo autor do post vem primeiro; em perguntas, depois dele vêm a
+// resposta aceita e as demais pelo saldo de votos.
+func (repo CommentsRepository) GetThreads(viewerID, postID uint64, limit, offset int) ([]model.CommentResponse, error) {
+	thread, threadArgs := threadClause(viewerID)
+
+	args := append([]interface{}{postID}, threadArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := repo.db.Query(`
+        SELECT `+commentResponseColumns+`
+        FROM comments c
+        LEFT JOIN users u ON u.id = c.author_id
+        WHERE c.post_id = ? AND c.parent_id IS NULL AND `+thread+`
+        ORDER BY `+commentOrder+`, c.createdAt ASC, c.id ASC
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCommentResponses(repo.db, viewerID, rows)
+}
+
+// Respostas diretas a um comentário, com a contagem de respostas de cada uma, paginadas
+func (repo CommentsRepository) GetReplies(viewerID, commentID uint64, limit, offset int) ([]model.CommentResponse, error) {
+	thread, threadArgs := threadClause(viewerID)
+
+	args := append([]interface{}{commentID}, threadArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := repo.db.Query(`
+        SELECT `+commentResponseColumns+`
+        FROM comments c
+        LEFT JOIN users u ON u.id = c.author_id
+        WHERE c.parent_id = ? AND `+thread+`
+        ORDER BY c.createdAt ASC, c.id ASC
+        LIMIT ? OFFSET ?
+    `, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanCommentResponses(repo.db, viewerID, rows)
+}
+
+// Busca o post e a profundidade de um comentário ativo, para validar uma resposta a ele.
+// Comentários de usuários com bloqueio em relação a quem responde, ou ocultados pelo
+// autor do post, não são encontrados.
+func (repo CommentsRepository) GetThreadPosition(viewerID, commentID uint64) (postID uint64, depth int, err error) {
+	notBlocked, args := notBlockedClause("c.author_id", viewerID)
+	notHidden, hiddenArgs := notHiddenClause(viewerID)
+
+	args = append([]interface{}{commentID}, args...)
+	err = repo.db.QueryRow(
+		"SELECT c.post_id, c.depth FROM comments c WHERE c.id = ? AND c.deleted_at IS NULL AND "+notBlocked+" AND "+notHidden,
+		append(args, hiddenArgs...)...,
+	).Scan(&postID, &depth)
+	return postID, depth, err
+}
+
+// Busca o post de um comentário, mesmo removido, para abrir sua lista de respostas
+func (repo CommentsRepository) GetPostID(commentID uint64) (uint64, error) {
+	var postID uint64
+	err := repo.db.QueryRow("SELECT post_id FROM comments WHERE id = ?", commentID).Scan(&postID)
+	return postID, err
+}
+
+// scanCommentResponses lê comentários no formato de commentResponseColumns. Os
+// removidos perdem conteúdo, autor, anexos e reações, ficando só o marcador.
+func scanCommentResponses(db *sql.DB, viewerID uint64, rows *sql.Rows) ([]model.CommentResponse, error) {
+	comments := []model.CommentResponse{}
+	var commentIDs []uint64
+
+	for rows.Next() {
+		var c model.CommentResponse
+		var author model.CommentAuthor
+		var authorAvatar, avatarVariants *string
+
+		err := rows.Scan(
+			&c.ID,
+			&c.ParentID,
+			&c.Depth,
+			&c.Content,
+			&c.CreatedAt,
+			&c.EditedAt,
+			&c.Removed,
+			&c.Hidden,
+			&c.Pinned,
+			&c.Score,
+			&c.Accepted,
+			&author.ID,
+			&author.Name,
+			&author.Nick,
+			&authorAvatar,
+			&avatarVariants,
+			&c.ReplyCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if c.Removed {
+			c.Content = model.RemovedCommentPlaceholder
+			c.ContentHTML = ""
+			c.EditedAt = nil
+			c.Hidden = false
+			c.Pinned = false
+			c.Score = 0
+			c.Accepted = false
+			c.Attachments = []model.Attachment{}
+			c.Reactions = []model.ReactionCount{}
+			c.MyReactions = []string{}
+			comments = append(comments, c)
+			continue
+		}
+
+		author.AvatarURL = avatar.URL(author.ID, authorAvatar, avatarVariants)
+		c.Author = &author
+		c.ContentHTML = render.Comment(c.ID, c.Content)
+
+		comments = append(comments, c)
+		commentIDs = append(commentIDs, c.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	attachments, err := attachmentsFor(db, "comment_id", commentIDs)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := reactionSummaries(db, viewerID, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := commentVotes(db, viewerID, commentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range comments {
+		if !comments[i].Removed {
+			comments[i].Attachments = attachmentsOrEmpty(attachments[comments[i].ID])
+			comments[i].ReactionSummary = reactions[comments[i].ID]
+			comments[i].MyVote = votes[comments[i].ID]
+		}
+	}
+
+	return comments, nil
+}
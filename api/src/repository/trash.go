@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"api/src/model"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+type TrashRepository struct {
+	db *sql.DB
+}
+
+// Cria um novo repositório da lixeira
+func NewTrashRepository(db *sql.DB) *TrashRepository {
+	return &TrashRepository{db}
+}
+
+// Lista os posts excluídos do usuário
+func (r TrashRepository) GetPosts(userID uint64) ([]model.Post, error) {
+	rows, err := r.db.Query(`
+        SELECT p.id, p.title, p.content, p.author_id, u.nick, p.visibility, p.status, p.createdAt, p.deleted_at
+        FROM posts p
+        LEFT JOIN users u ON u.id = p.author_id
+        WHERE p.author_id = ? AND p.deleted_at IS NOT NULL
+        ORDER BY p.deleted_at DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	posts := []model.Post{}
+
+	for rows.Next() {
+		var post model.Post
+		if err := rows.Scan(
+			&post.ID,
+			&post.Title,
+			&post.Content,
+			&post.AuthorID,
+			&post.AuthorNickname,
+			&post.Visibility,
+			&post.Status,
+			&post.CreatedAt,
+			&post.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, rows.Err()
+}
+
+// Lista os comentários excluídos pelo próprio usuário (marcadores já esvaziados pela
+// limpeza e comentários removidos pelo autor do post ficam de fora)
+func (r TrashRepository) GetComments(userID uint64) ([]model.Comment, error) {
+	rows, err := r.db.Query(`
+        SELECT id, post_id, author_id, content, createdAt, deleted_at
+        FROM comments
+        WHERE author_id = ? AND deleted_at IS NOT NULL AND content <> ''
+          AND (deleted_by IS NULL OR deleted_by = author_id)
+        ORDER BY deleted_at DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []model.Comment{}
+
+	for rows.Next() {
+		var comment model.Comment
+		if err := rows.Scan(
+			&comment.ID,
+			&comment.PostID,
+			&comment.AuthorID,
+			&comment.Content,
+			&comment.CreatedAt,
+			&comment.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// Restaura um post da lixeira do usuário
+func (r TrashRepository) RestorePost(userID, postID uint64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE posts SET deleted_at = NULL WHERE id = ? AND author_id = ? AND deleted_at IS NOT NULL", postID, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	// De volta da lixeira, o post e suas curtidas voltam a contar no perfil
+	if err := adjustPostCounters(tx, postID, 1); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Restaura um comentário da lixeira do usuário
+func (r TrashRepository) RestoreComment(userID, commentID uint64) error {
+	return r.restore(`
+        UPDATE comments SET deleted_at = NULL, deleted_by = NULL
+        WHERE id = ? AND author_id = ? AND deleted_at IS NOT NULL AND content <> ''
+          AND (deleted_by IS NULL OR deleted_by = author_id)
+    `, commentID, userID)
+}
+
+func (r TrashRepository) restore(query string, id, userID uint64) error {
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Remove definitivamente os posts excluídos antes da data informada e retorna seus IDs.
+// Curtidas, comentários e revisões são apagados em cascata pelo banco.
+func (r TrashRepository) PurgePosts(before time.Time, limit int) ([]uint64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+        SELECT id FROM posts
+        WHERE deleted_at IS NOT NULL AND deleted_at < ?
+        LIMIT ?
+        FOR UPDATE SKIP LOCKED
+    `, before, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if err := releaseReputation(tx, "post_id = ?", id); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec("DELETE FROM posts WHERE id = ?", id); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Remove definitivamente os comentários excluídos antes da data informada.
+// Comentários que ainda têm respostas continuam como marcador "[removed]": só o
+// texto é apagado, e a linha sai quando as respostas deixarem de existir.
+func (r TrashRepository) PurgeComments(before time.Time, limit int) (int64, error) {
+	_, err := r.db.Exec(`
+        UPDATE comments c
+        JOIN comments ch ON ch.parent_id = c.id
+        SET c.content = ''
+        WHERE c.deleted_at IS NOT NULL AND c.deleted_at < ? AND c.content <> ''
+    `, before)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := r.db.Query(`
+        SELECT c.id FROM comments c
+        WHERE c.deleted_at IS NOT NULL AND c.deleted_at < ?
+          AND NOT EXISTS(SELECT 1 FROM comments ch WHERE ch.parent_id = c.id)
+        LIMIT ?
+    `, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var ids []interface{}
+	for rows.Next() {
+		var id uint64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	if err := releaseReputation(tx, "comment_id IN ("+placeholders+")", ids...); err != nil {
+		return 0, err
+	}
+
+	result, err := tx.Exec("DELETE FROM comments WHERE id IN ("+placeholders+")", ids...)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return purged, tx.Commit()
+}
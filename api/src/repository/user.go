@@ -8,6 +8,10 @@ import (
 	"log"
 )
 
+// Retornado ao seguir um usuário inexistente ou com bloqueio entre os dois;
+// os dois casos são indistinguíveis para quem tenta seguir
+var ErrFollowTargetNotFound = errors.New("usuário a ser seguido não existe")
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -19,13 +23,19 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 
 // Insere um usuário no banco de dados e retorna o ID do usuário criado
 func (u UserRepository) Create(user model.User) (uint64, error) {
-	statement, err := u.db.Prepare("insert into users (name, nick, email, password) values (?, ?, ?, ?)")
+	tx, err := u.db.Begin()
 	if err != nil {
-		log.Println("Erro ao preparar a declaração de inserção:", err)
 		return 0, err
 	}
-	defer statement.Close()
-	result, err := statement.Exec(user.Name, user.Nick, user.Email, user.Password)
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+        INSERT INTO users (name, nick, email, password, bio, website, location, pronouns, github, gitlab, is_private)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    `, user.Name, user.Nick, user.Email, user.Password,
+		nullable(user.Bio), nullable(user.Website), nullable(user.Location),
+		nullable(user.Pronouns), nullable(user.GitHub), nullable(user.GitLab),
+		user.Private != nil && *user.Private)
 	if err != nil {
 		log.Println("Erro ao executar a declaração de inserção:", err)
 		return 0, err
@@ -35,35 +45,33 @@ func (u UserRepository) Create(user model.User) (uint64, error) {
 		log.Println("Erro ao obter o ID do último registro inserido:", err)
 		return 0, err
 	}
-	return uint64(lastInsertId), nil
+
+	if err := replaceTechStack(tx, uint64(lastInsertId), user.TechStack); err != nil {
+		return 0, err
+	}
+
+	return uint64(lastInsertId), tx.Commit()
 }
 
-// Busca todos os usuários cujo nome ou nick contenham o termo fornecido
-func (u UserRepository) GetAll(nameOrNick string) ([]model.User, error) {
+// Busca todos os usuários cujo nome ou nick contenham o termo fornecido,
+// exceto os que têm bloqueio com quem busca
+func (u UserRepository) GetAll(viewerID uint64, nameOrNick string) ([]model.User, error) {
 	nameOrNick = fmt.Sprintf("%%%s%%", nameOrNick) // adiciona % para busca parcial
 
-	query := "SELECT id, name, nick, email, createdAt FROM users WHERE name LIKE ? OR nick LIKE ?"
+	notBlocked, blockArgs := notBlockedClause("u.id", viewerID)
+
+	query := "SELECT " + userColumns + " FROM users u WHERE (u.name LIKE ? OR u.nick LIKE ?) AND " + notBlocked
 
-	rows, err := u.db.Query(query, nameOrNick, nameOrNick)
+	rows, err := u.db.Query(query, append([]interface{}{nameOrNick, nameOrNick}, blockArgs...)...)
 	if err != nil {
 		log.Println("Erro ao executar a query de seleção:", err)
 		return nil, err
 	}
 	defer rows.Close()
 
-	var users []model.User
-
-	for rows.Next() {
-		var user model.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Nick, &user.Email, &user.CreatedAt); err != nil {
-			log.Println("Erro ao escanear o usuário:", err)
-			return nil, err
-		}
-		users = append(users, user)
-	}
-
-	if err := rows.Err(); err != nil {
-		log.Println("Erro durante a leitura das linhas:", err)
+	users, err := scanUsers(u.db, rows)
+	if err != nil {
+		log.Println("Erro ao escanear os usuários:", err)
 		return nil, err
 	}
 
@@ -71,17 +79,12 @@ func (u UserRepository) GetAll(nameOrNick string) ([]model.User, error) {
 }
 func (u UserRepository) GetByID(id uint64) (model.User, error) {
 	var user model.User
+	var row userRow
 
-	query := "SELECT id, name, nick, email, createdAt FROM users WHERE id = ?"
+	query := "SELECT " + userColumns + " FROM users u WHERE u.id = ?"
 
 	// Executa a query e escaneia o resultado
-	err := u.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Nick,
-		&user.Email,
-		&user.CreatedAt,
-	)
+	err := u.db.QueryRow(query, id).Scan(userFields(&user, &row)...)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -92,18 +95,42 @@ func (u UserRepository) GetByID(id uint64) (model.User, error) {
 		return user, errors.New("erro ao buscar usuário")
 	}
 
+	row.finish(&user)
+
+	stacks, err := techStacks(u.db, []uint64{user.ID})
+	if err != nil {
+		log.Println("Erro ao buscar stack do usuário:", err)
+		return user, errors.New("erro ao buscar usuário")
+	}
+	user.TechStack = stacks[user.ID]
+
+	badges, err := userBadges(u.db, []uint64{user.ID})
+	if err != nil {
+		log.Println("Erro ao buscar conquistas do usuário:", err)
+		return user, errors.New("erro ao buscar usuário")
+	}
+	user.Badges = badges[user.ID]
+
 	return user, nil
 }
 
 // Atualiza um usuário pelo ID
 func (u UserRepository) Update(id uint64, user model.User) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
 	// Buscar dados atuais
 	var current model.User
-	err := u.db.QueryRow(`
-        SELECT name, nick, email, password 
-        FROM users WHERE id = ?
-    `, id).Scan(&current.Name, &current.Nick, &current.Email, &current.Password)
+	var wasPrivate bool
+	err = tx.QueryRow(`
+        SELECT email, password, bio, website, location, pronouns, github, gitlab, is_private
+        FROM users WHERE id = ? FOR UPDATE
+    `, id).Scan(&current.Email, &current.Password,
+		&current.Bio, &current.Website, &current.Location, &current.Pronouns, &current.GitHub, &current.GitLab, &wasPrivate)
+	current.Private = &wasPrivate
 
 	if err != nil {
 		return errors.New("usuário não encontrado")
@@ -119,26 +146,74 @@ func (u UserRepository) Update(id uint64, user model.User) error {
 		user.Password = current.Password
 	}
 
+	// Campos do perfil não enviados → mantém os atuais
+	keepIfMissing(&user.Profile, &current.Profile)
+
 	query := `
         UPDATE users 
-        SET name = ?, nick = ?, email = ?, password = ?
+        SET name = ?, nick = ?, email = ?, password = ?,
+            bio = ?, website = ?, location = ?, pronouns = ?, github = ?, gitlab = ?, is_private = ?
         WHERE id = ?
     `
 
-	_, err = u.db.Exec(query,
+	_, err = tx.Exec(query,
 		user.Name,
 		user.Nick,
 		user.Email,
 		user.Password,
+		nullable(user.Bio),
+		nullable(user.Website),
+		nullable(user.Location),
+		nullable(user.Pronouns),
+		nullable(user.GitHub),
+		nullable(user.GitLab),
+		*user.Private,
 		id,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Ao tornar a conta pública, os pedidos pendentes são aceitos
+	if wasPrivate && !*user.Private {
+		if err := acceptAllRequests(tx, id); err != nil {
+			return err
+		}
+	}
+
+	// A stack só é trocada quando enviada; uma lista vazia a limpa
+	if user.TechStack != nil {
+		if err := replaceTechStack(tx, id, user.TechStack); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
 // Deleta um usuário pelo ID
 func (u UserRepository) Delete(id uint64) error {
-	result, err := u.db.Exec("DELETE FROM users WHERE id = ?", id)
+	tx, err := u.db.Begin()
+	if err != nil {
+		return fmt.Errorf("erro ao deletar usuário: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := releaseUserCounters(tx, id); err != nil {
+		return fmt.Errorf("erro ao atualizar contadores: %w", err)
+	}
+
+	// Eventos em que o usuário agiu ou que envolvem seus posts somem em cascata
+	err = releaseReputation(tx, "actor_id = ? OR post_id IN (SELECT id FROM posts WHERE author_id = ?)", id, id)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar reputação: %w", err)
+	}
+
+	if err := releaseUserComments(tx, id); err != nil {
+		return fmt.Errorf("erro ao remover comentários: %w", err)
+	}
+
+	result, err := tx.Exec("DELETE FROM users WHERE id = ?", id)
 	if err != nil {
 		return fmt.Errorf("erro ao deletar usuário: %w", err)
 	}
@@ -153,7 +228,30 @@ func (u UserRepository) Delete(id uint64) error {
 		return errors.New("usuário não encontrado")
 	}
 
-	return nil
+	return tx.Commit()
+}
+
+// releaseUserComments remove os comentários do usuário que será excluído. Os que têm
+// respostas viram marcadores "[removed]", sem texto, para não quebrar as conversas de
+// outros usuários; o autor passa a NULL quando o usuário é apagado. Os demais são
+// apagados de fato.
+func releaseUserComments(ex execer, userID uint64) error {
+	_, err := ex.Exec(`
+        UPDATE comments c
+        JOIN comments ch ON ch.parent_id = c.id
+        SET c.deleted_at = COALESCE(c.deleted_at, CURRENT_TIMESTAMP), c.content = '', c.pinned_at = NULL
+        WHERE c.author_id = ?
+    `, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = ex.Exec(`
+        DELETE c FROM comments c
+        LEFT JOIN comments ch ON ch.parent_id = c.id
+        WHERE c.author_id = ? AND ch.id IS NULL
+    `, userID)
+	return err
 }
 
 // Busca um usuário pelo email
@@ -180,23 +278,29 @@ func (u UserRepository) FindByEmail(email string) (model.User, error) {
 }
 
 // Permite que um usuário siga outro
-// Seguir usuário
-func (u UserRepository) Follow(currentUserID, targetUserID uint64) error {
+// Seguir usuário. Em contas privadas cria um pedido pendente e retorna requested = true.
+func (u UserRepository) Follow(currentUserID, targetUserID uint64) (requested bool, err error) {
 	if currentUserID == targetUserID {
-		return errors.New("você não pode seguir você mesmo")
+		return false, errors.New("você não pode seguir você mesmo")
 	}
 
-	// Verifica se usuário a ser seguido existe
-	var exists bool
-	err := u.db.QueryRow("SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)", targetUserID).Scan(&exists)
-	if err != nil {
-		return err
+	// Verifica se usuário a ser seguido existe; com bloqueio, responde como se não existisse
+	notBlocked, blockArgs := notBlockedClause("id", currentUserID)
+
+	var private bool
+	err = u.db.QueryRow(
+		"SELECT is_private FROM users WHERE id = ? AND "+notBlocked,
+		append([]interface{}{targetUserID}, blockArgs...)...,
+	).Scan(&private)
+	if err == sql.ErrNoRows {
+		return false, ErrFollowTargetNotFound
 	}
-	if !exists {
-		return errors.New("usuário a ser seguido não existe")
+	if err != nil {
+		return false, err
 	}
 
 	// Verifica se já segue
+	var exists bool
 	err = u.db.QueryRow(`
         SELECT EXISTS(
             SELECT 1 FROM followers 
@@ -204,18 +308,45 @@ func (u UserRepository) Follow(currentUserID, targetUserID uint64) error {
         )
     `, currentUserID, targetUserID).Scan(&exists)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if exists {
-		return errors.New("você já está seguindo este usuário")
+		return false, errors.New("você já está seguindo este usuário")
+	}
+
+	if private {
+		_, err = u.db.Exec(`
+            INSERT INTO follow_requests (requester_id, target_id)
+            VALUES (?, ?)
+            ON DUPLICATE KEY UPDATE requester_id = requester_id
+        `, currentUserID, targetUserID)
+		return true, err
 	}
 
+	tx, err := u.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
 	// Insere o follow
-	_, err = u.db.Exec(`
+	_, err = tx.Exec(`
         INSERT INTO followers (follower_id, following_id)
         VALUES (?, ?)
     `, currentUserID, targetUserID)
-	return err
+	if err != nil {
+		return false, err
+	}
+
+	if err := adjustFollowCounters(tx, currentUserID, targetUserID, 1); err != nil {
+		return false, err
+	}
+
+	if err := grantFollowReputation(tx, currentUserID, targetUserID); err != nil {
+		return false, err
+	}
+
+	return false, tx.Commit()
 }
 
 // Deixar de seguir usuário
@@ -224,7 +355,13 @@ func (u UserRepository) Unfollow(currentUserID, targetUserID uint64) error {
 		return errors.New("você não pode deixar de seguir você mesmo")
 	}
 
-	result, err := u.db.Exec(`
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
         DELETE FROM followers
         WHERE follower_id = ? AND following_id = ?
     `, currentUserID, targetUserID)
@@ -237,11 +374,27 @@ func (u UserRepository) Unfollow(currentUserID, targetUserID uint64) error {
 		return err
 	}
 
+	// Sem follow, talvez exista apenas um pedido pendente: deixar de seguir o cancela
 	if rowsAffected == 0 {
-		return errors.New("relacionamento de follow não encontrado")
+		canceled, err := deleteFollowRequest(tx, currentUserID, targetUserID)
+		if err != nil {
+			return err
+		}
+		if !canceled {
+			return errors.New("relacionamento de follow não encontrado")
+		}
+		return tx.Commit()
 	}
 
-	return nil
+	if err := adjustFollowCounters(tx, currentUserID, targetUserID, -1); err != nil {
+		return err
+	}
+
+	if err := revokeFollowReputation(tx, currentUserID, targetUserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // Verifica se um usuário segue outro
@@ -256,10 +409,30 @@ func (u UserRepository) IsFollowing(currentUserID, targetUserID uint64) (bool, e
 	return exists, err
 }
 
+// Retorna a relação de follow entre quem consulta e outro usuário, nos dois sentidos
+func (u UserRepository) Relationship(viewerID, targetID uint64) (model.Relationship, error) {
+	var relationship model.Relationship
+	err := u.db.QueryRow(`
+        SELECT
+            EXISTS(SELECT 1 FROM followers WHERE follower_id = ? AND following_id = ?),
+            EXISTS(SELECT 1 FROM followers WHERE follower_id = ? AND following_id = ?),
+            EXISTS(SELECT 1 FROM follow_requests WHERE requester_id = ? AND target_id = ?),
+            EXISTS(
+                SELECT 1 FROM muted_users
+                WHERE user_id = ? AND muted_id = ? AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+            )
+    `, viewerID, targetID, targetID, viewerID, viewerID, targetID, viewerID, targetID).Scan(
+		&relationship.Following, &relationship.FollowedBy, &relationship.Requested, &relationship.Muted,
+	)
+
+	relationship.Mutual = relationship.Following && relationship.FollowedBy
+	return relationship, err
+}
+
 // Lista os seguidores de um usuário (quem segue o userID)
 func (u UserRepository) GetFollowers(userID uint64) ([]model.User, error) {
 	rows, err := u.db.Query(`
-        SELECT u.id, u.name, u.nick, u.email, u.createdAt
+        SELECT `+userColumns+`
         FROM users u
         INNER JOIN followers f ON u.id = f.follower_id
         WHERE f.following_id = ?
@@ -269,21 +442,13 @@ func (u UserRepository) GetFollowers(userID uint64) ([]model.User, error) {
 	}
 	defer rows.Close()
 
-	var followers []model.User
-	for rows.Next() {
-		var follower model.User
-		if err := rows.Scan(&follower.ID, &follower.Name, &follower.Nick, &follower.Email, &follower.CreatedAt); err != nil {
-			return nil, err
-		}
-		followers = append(followers, follower)
-	}
-	return followers, nil
+	return scanUsers(u.db, rows)
 }
 
 // Lista os usuários que o userID está seguindo
 func (u UserRepository) GetFollowing(userID uint64) ([]model.User, error) {
 	rows, err := u.db.Query(`
-        SELECT u.id, u.name, u.nick, u.email, u.createdAt
+        SELECT `+userColumns+`
         FROM users u
         INNER JOIN followers f ON u.id = f.following_id
         WHERE f.follower_id = ?
@@ -293,15 +458,14 @@ func (u UserRepository) GetFollowing(userID uint64) ([]model.User, error) {
 	}
 	defer rows.Close()
 
-	var following []model.User
-	for rows.Next() {
-		var user model.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.Nick, &user.Email, &user.CreatedAt); err != nil {
-			return nil, err
-		}
-		following = append(following, user)
-	}
-	return following, nil
+	return scanUsers(u.db, rows)
+}
+
+// Retorna o papel do usuário (model.RoleUser, RoleModerator ou RoleAdmin)
+func (u UserRepository) GetRole(userID uint64) (string, error) {
+	var role string
+	err := u.db.QueryRow("SELECT role FROM users WHERE id = ?", userID).Scan(&role)
+	return role, err
 }
 
 // Retorna a senha do usuário pelo ID
@@ -335,3 +499,43 @@ func (u UserRepository) UpdatePassword(userID uint64, newPassword string) error
 
 	return nil
 }
+
+// Troca a foto de perfil do usuário (nil remove) junto com a lista de tamanhos gerados,
+// e retorna a chave e a lista da foto anterior, para que seus arquivos sejam apagados
+// do armazenamento
+func (u UserRepository) UpdateAvatar(userID uint64, key, variants *string) (*string, *string, error) {
+	return u.replaceImage("avatar_key", "avatar_variants", userID, key, variants)
+}
+
+// Troca a imagem de capa do perfil, nos mesmos moldes de UpdateAvatar
+func (u UserRepository) UpdateBanner(userID uint64, key, variants *string) (*string, *string, error) {
+	return u.replaceImage("banner_key", "banner_variants", userID, key, variants)
+}
+
+// replaceImage grava a nova chave e a lista de tamanhos nas colunas indicadas e
+// devolve as anteriores
+func (u UserRepository) replaceImage(keyColumn, variantsColumn string, userID uint64, key, variants *string) (*string, *string, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var previous, previousVariants *string
+	err = tx.QueryRow(
+		"SELECT "+keyColumn+", "+variantsColumn+" FROM users WHERE id = ? FOR UPDATE", userID,
+	).Scan(&previous, &previousVariants)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, errors.New("usuário não encontrado")
+		}
+		return nil, nil, err
+	}
+
+	_, err = tx.Exec("UPDATE users SET "+keyColumn+" = ?, "+variantsColumn+" = ? WHERE id = ?", key, variants, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao atualizar imagem do perfil: %w", err)
+	}
+
+	return previous, previousVariants, tx.Commit()
+}
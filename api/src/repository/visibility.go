@@ -0,0 +1,109 @@
+package repository
+
+// Regras de visibilidade dos posts, compartilhadas por todas as consultas de leitura.
+// As condições assumem que a tabela posts foi apelidada de "p".
+
+// postVisibleClause retorna a condição SQL que limita os posts aos publicados que o
+// usuário pode abrir diretamente (pelo link), junto com os argumentos dos placeholders.
+// Posts de contas privadas só aparecem para seguidores aprovados, e posts de usuários
+// bloqueados (em qualquer direção) não aparecem.
+func postVisibleClause(viewerID uint64) (string, []interface{}) {
+	notBlocked, blockArgs := notBlockedClause("p.author_id", viewerID)
+
+	clause := `(
+        p.status = 'published'
+        AND p.deleted_at IS NULL
+        AND (
+            p.author_id = ?
+            OR (
+                (
+                    p.visibility IN ('public', 'unlisted')
+                    OR (p.visibility = 'followers' AND EXISTS(
+                        SELECT 1 FROM followers vf
+                        WHERE vf.follower_id = ? AND vf.following_id = p.author_id
+                    ))
+                )
+                AND (
+                    NOT EXISTS(SELECT 1 FROM users va WHERE va.id = p.author_id AND va.is_private)
+                    OR EXISTS(
+                        SELECT 1 FROM followers pf
+                        WHERE pf.follower_id = ? AND pf.following_id = p.author_id
+                    )
+                )
+                AND ` + notBlocked + `
+            )
+        )
+    )`
+
+	return clause, append([]interface{}{viewerID, viewerID, viewerID}, blockArgs...)
+}
+
+// postFeedClause restringe ainda mais a condição de visibilidade para listagens:
+// posts não listados só podem ser abertos pelo link, e contas e palavras silenciadas
+// pelo usuário ficam de fora. Todo feed, cronológico ou não, deve partir daqui.
+func postFeedClause(viewerID uint64) (string, []interface{}) {
+	clause, args := postVisibleClause(viewerID)
+	notMuted, mutedArgs := notMutedClause(viewerID, "p.author_id", "CONCAT_WS(' ', p.title, p.content)")
+	return clause + " AND p.visibility <> 'unlisted' AND " + notMuted, append(args, mutedArgs...)
+}
+
+// postOwnedOrVisibleClause também libera ao autor seus próprios rascunhos,
+// para que possa editá-los, publicá-los ou excluí-los. Posts na lixeira ficam de fora.
+func postOwnedOrVisibleClause(viewerID uint64) (string, []interface{}) {
+	clause, args := postVisibleClause(viewerID)
+	return "(p.deleted_at IS NULL AND (p.author_id = ? OR " + clause + "))", append([]interface{}{viewerID}, args...)
+}
+
+// notBlockedClause exclui as linhas cujo usuário (na coluna informada) bloqueou quem
+// consulta ou foi bloqueado por ele
+func notBlockedClause(column string, viewerID uint64) (string, []interface{}) {
+	clause := `NOT EXISTS(
+        SELECT 1 FROM blocks b
+        WHERE (b.blocker_id = ? AND b.blocked_id = ` + column + `)
+           OR (b.blocker_id = ` + column + ` AND b.blocked_id = ?)
+    )`
+
+	return clause, []interface{}{viewerID, viewerID}
+}
+
+// notMutedClause esconde o conteúdo de contas silenciadas por quem consulta e o que
+// contém alguma de suas palavras silenciadas (o próprio conteúdo dele nunca é escondido).
+// Silêncios vencidos são ignorados mesmo antes de o agendador apagá-los.
+func notMutedClause(viewerID uint64, authorColumn, textExpr string) (string, []interface{}) {
+	clause := `(` + authorColumn + ` = ? OR (
+        NOT EXISTS(
+            SELECT 1 FROM muted_users mu
+            WHERE mu.user_id = ? AND mu.muted_id = ` + authorColumn + `
+              AND (mu.expires_at IS NULL OR mu.expires_at > CURRENT_TIMESTAMP)
+        )
+        AND NOT EXISTS(
+            SELECT 1 FROM muted_keywords mk
+            WHERE mk.user_id = ?
+              AND (mk.expires_at IS NULL OR mk.expires_at > CURRENT_TIMESTAMP)
+              AND REGEXP_LIKE(` + textExpr + `, mk.pattern, IF(mk.case_sensitive, 'c', 'i'))
+        )
+    ))`
+
+	return clause, []interface{}{viewerID, viewerID, viewerID}
+}
+
+// commentHiddenClause reúne bloqueios, silêncios e a moderação do autor do post para
+// listagens de comentários (tabela comments apelidada de "c")
+func commentHiddenClause(viewerID uint64) (string, []interface{}) {
+	notBlocked, args := notBlockedClause("c.author_id", viewerID)
+	notMuted, mutedArgs := notMutedClause(viewerID, "c.author_id", "c.content")
+	notHidden, hiddenArgs := notHiddenClause(viewerID)
+
+	args = append(args, mutedArgs...)
+	return notBlocked + " AND " + notMuted + " AND " + notHidden, append(args, hiddenArgs...)
+}
+
+// notHiddenClause esconde os comentários ocultados pelo autor do post, que continuam
+// visíveis apenas para ele e para quem os escreveu
+func notHiddenClause(viewerID uint64) (string, []interface{}) {
+	clause := `(c.hidden_at IS NULL OR c.author_id = ? OR EXISTS(
+        SELECT 1 FROM posts hp WHERE hp.id = c.post_id AND hp.author_id = ?
+    ))`
+
+	return clause, []interface{}{viewerID, viewerID}
+}
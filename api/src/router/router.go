@@ -1,8 +1,11 @@
 package router
 
 import (
+	"api/src/config"
 	"api/src/middleware"
 	"api/src/router/routes"
+	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
@@ -14,5 +17,23 @@ func Generate() *mux.Router {
 
 	routes.SettingRoutes(r)
 
+	// No armazenamento local, a própria API serve os arquivos enviados
+	if config.StorageDriver == "local" {
+		r.PathPrefix("/uploads/").Handler(
+			http.StripPrefix("/uploads/", noDirectoryListing(http.FileServer(http.Dir(config.StorageLocalDir)))),
+		)
+	}
+
 	return r
 }
+
+// noDirectoryListing impede que o conteúdo dos diretórios de upload seja listado
+func noDirectoryListing(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "" || strings.HasSuffix(r.URL.Path, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
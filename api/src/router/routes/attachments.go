@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesAttachments = []Route{
+	{
+		Uri:            "/posts/{postId}/attachments",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.UploadPostAttachment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/attachments",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.UploadCommentAttachment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/attachments/{id}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.DeleteAttachment,
+		Authentication: true,
+	},
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesBadges = []Route{
+	{
+		Uri:            "/badges",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetBadges,
+		Authentication: true,
+	},
+	{
+		Uri:            "/badges/{slug}/holders",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetBadgeHolders,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/badges/{slug}",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.GrantBadge,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/badges/{slug}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.RevokeBadge,
+		Authentication: true,
+	},
+}
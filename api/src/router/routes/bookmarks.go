@@ -0,0 +1,87 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesBookmarks = []Route{
+	{
+		Uri:            "/posts/{postId}/bookmark",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.AddBookmark,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/bookmark",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.RemoveBookmark,
+		Authentication: true,
+	},
+	{
+		Uri:            "/bookmarks",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetBookmarks,
+		Authentication: true,
+	},
+	{
+		Uri:            "/bookmarks/order",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.ReorderBookmarks,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.CreateCollection,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetMyCollections,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/collections",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetUserCollections,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections/{id}",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetCollection,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections/{id}",
+		Methods:        []string{http.MethodPatch, http.MethodOptions},
+		Function:       controllers.UpdateCollection,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections/{id}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.DeleteCollection,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections/{id}/posts/{postId}",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.AddToCollection,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections/{id}/posts/{postId}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.RemoveFromCollection,
+		Authentication: true,
+	},
+	{
+		Uri:            "/collections/{id}/order",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.ReorderCollection,
+		Authentication: true,
+	},
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesFollowRequests = []Route{
+	{
+		Uri:            "/follow-requests/incoming",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetIncomingRequests,
+		Authentication: true,
+	},
+	{
+		Uri:            "/follow-requests/outgoing",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetOutgoingRequests,
+		Authentication: true,
+	},
+	{
+		Uri:            "/follow-requests/{userId}/approve",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.ApproveFollowRequest,
+		Authentication: true,
+	},
+	{
+		Uri:            "/follow-requests/{userId}/reject",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.RejectFollowRequest,
+		Authentication: true,
+	},
+	{
+		Uri:            "/followers/{userId}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.RemoveFollower,
+		Authentication: true,
+	},
+}
@@ -0,0 +1,51 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesMutes = []Route{
+	{
+		Uri:            "/users/{userId}/mute",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.MuteUser,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/mute",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UnmuteUser,
+		Authentication: true,
+	},
+	{
+		Uri:            "/mutes/users",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetMutedUsers,
+		Authentication: true,
+	},
+	{
+		Uri:            "/mutes/keywords",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetMutedKeywords,
+		Authentication: true,
+	},
+	{
+		Uri:            "/mutes/keywords",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.CreateMutedKeyword,
+		Authentication: true,
+	},
+	{
+		Uri:            "/mutes/keywords/{keywordId}",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.UpdateMutedKeyword,
+		Authentication: true,
+	},
+	{
+		Uri:            "/mutes/keywords/{keywordId}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.DeleteMutedKeyword,
+		Authentication: true,
+	},
+}
@@ -36,6 +36,66 @@ var routesPost = []Route{
 		Function:       controllers.DeletePost,
 		Authentication: true,
 	},
+	{
+		Uri:            "/posts/{postId}/publish",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.PublishPost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/schedule",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.SchedulePost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/schedule",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.CancelSchedule,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/revisions",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetRevisions,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/revisions/diff",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.DiffRevisions,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/revisions/{revisionId:[0-9]+}",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetRevision,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/revisions/{revisionId:[0-9]+}/restore",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.RestoreRevision,
+		Authentication: true,
+	},
+	{
+		Uri:            "/drafts",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.CreateDraft,
+		Authentication: true,
+	},
+	{
+		Uri:            "/drafts",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetDrafts,
+		Authentication: true,
+	},
+	{
+		Uri:            "/drafts/{postId}/autosave",
+		Methods:        []string{http.MethodPatch, http.MethodOptions},
+		Function:       controllers.AutosaveDraft,
+		Authentication: true,
+	},
 	{
 		Uri:            "/posts/{postId}/like",
 		Methods:        []string{http.MethodPost, http.MethodOptions},
@@ -48,6 +108,48 @@ var routesPost = []Route{
 		Function:       controllers.UnlikePost,
 		Authentication: true,
 	},
+	{
+		Uri:            "/posts/{postId}/reactions",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetPostReactions,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/reactions",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UnlikePost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/reactions/{emoji}",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.ReactToPost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/repost",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.RepostPost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/repost",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UndoRepost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/reposts",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetPostReposts,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/quotes",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetPostQuotes,
+		Authentication: true,
+	},
 	{
 		Uri:            "/posts/{postId}/comments",
 		Methods:        []string{http.MethodGet, http.MethodOptions},
@@ -60,10 +162,112 @@ var routesPost = []Route{
 		Function:       controllers.CreateComment,
 		Authentication: true,
 	},
+	{
+		Uri:            "/posts/{postId}/comment-settings",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetCommentSettings,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/comment-settings",
+		Methods:        []string{http.MethodPatch, http.MethodOptions},
+		Function:       controllers.UpdateCommentSettings,
+		Authentication: true,
+	},
 	{
 		Uri:            "/comments/{id}",
 		Methods:        []string{http.MethodDelete, http.MethodOptions},
 		Function:       controllers.DeleteComment,
 		Authentication: true,
 	},
+	{
+		Uri:            "/comments/{id}",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.UpdateComment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/history",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetCommentHistory,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/hide",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.HideComment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/hide",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UnhideComment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/pin",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.PinComment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/pin",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UnpinComment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/vote",
+		Methods:        []string{http.MethodPut, http.MethodOptions},
+		Function:       controllers.VoteAnswer,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/vote",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.RemoveAnswerVote,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/accept",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.AcceptAnswer,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/accept",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UnacceptAnswer,
+		Authentication: true,
+	},
+	{
+		Uri:            "/posts/{postId}/threads",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetCommentThreads,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/replies",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetCommentReplies,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/reactions",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetCommentReactions,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/reactions/{emoji}",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.ReactToComment,
+		Authentication: true,
+	},
+	{
+		Uri:            "/comments/{id}/reactions/{emoji}",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.RemoveCommentReaction,
+		Authentication: true,
+	},
 }
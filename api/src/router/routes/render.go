@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesRender = []Route{
+	{
+		Uri:            "/render/preview",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.RenderPreview,
+		Authentication: true,
+	},
+	{
+		Uri:            "/render/highlight.css",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.HighlightStylesheet,
+		Authentication: false,
+	},
+}
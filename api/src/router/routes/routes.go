@@ -20,6 +20,13 @@ func SettingRoutes(router *mux.Router) {
 	routes := routeUsers
 	routes = append(routes, routeLogin)
 	routes = append(routes, routesPost...)
+	routes = append(routes, routesTrash...)
+	routes = append(routes, routesRender...)
+	routes = append(routes, routesAttachments...)
+	routes = append(routes, routesFollowRequests...)
+	routes = append(routes, routesMutes...)
+	routes = append(routes, routesBadges...)
+	routes = append(routes, routesBookmarks...)
 
 	for _, route := range routes {
 		methods := append(route.Methods, http.MethodOptions)
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"api/src/controllers"
+	"net/http"
+)
+
+var routesTrash = []Route{
+	{
+		Uri:            "/trash",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetTrash,
+		Authentication: true,
+	},
+	{
+		Uri:            "/trash/posts/{postId}/restore",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.RestorePost,
+		Authentication: true,
+	},
+	{
+		Uri:            "/trash/comments/{id}/restore",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.RestoreComment,
+		Authentication: true,
+	},
+}
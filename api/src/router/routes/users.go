@@ -74,4 +74,64 @@ var routeUsers = []Route{
 		Function:       controllers.UpdatePassword,
 		Authentication: true,
 	},
+
+	// FOTO DE PERFIL E CAPA
+	{
+		Uri:            "/users/{userId}/avatar",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.UploadAvatar,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/avatar",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.DeleteAvatar,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/avatar/identicon.png",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetIdenticon,
+		Authentication: false,
+	},
+	{
+		Uri:            "/users/{userId}/banner",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.UploadBanner,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/banner",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.DeleteBanner,
+		Authentication: true,
+	},
+
+	// REPUTAÇÃO
+	{
+		Uri:            "/users/{userId}/reputation",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetReputationHistory,
+		Authentication: true,
+	},
+
+	// BLOQUEIOS
+	{
+		Uri:            "/users/{userId}/block",
+		Methods:        []string{http.MethodPost, http.MethodOptions},
+		Function:       controllers.BlockUser,
+		Authentication: true,
+	},
+	{
+		Uri:            "/users/{userId}/block",
+		Methods:        []string{http.MethodDelete, http.MethodOptions},
+		Function:       controllers.UnblockUser,
+		Authentication: true,
+	},
+	{
+		Uri:            "/blocks",
+		Methods:        []string{http.MethodGet, http.MethodOptions},
+		Function:       controllers.GetBlockedUsers,
+		Authentication: true,
+	},
 }
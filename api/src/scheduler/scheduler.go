@@ -0,0 +1,206 @@
+// Tarefas em segundo plano executadas dentro do processo da API
+package scheduler
+
+import (
+	"api/src/config"
+	"api/src/database"
+	"api/src/model"
+	"api/src/repository"
+	"api/src/storage"
+	"context"
+	"log"
+	"time"
+)
+
+// Quantidade máxima de linhas processadas por lote
+const (
+	publishBatchSize = 100
+	purgeBatchSize   = 100
+	orphanBatchSize  = 100
+	mutesBatchSize   = 500
+	badgesBatchSize  = 500
+)
+
+// Momento da última verificação de conquistas, que roda a cada config.BadgeInterval
+var lastBadgeAward time.Time
+
+// Start inicia o agendador em uma goroutine. Todo o estado fica no banco,
+// então tarefas pendentes são retomadas após reinícios da API.
+func Start() {
+	go func() {
+		ticker := time.NewTicker(config.SchedulerInterval)
+		defer ticker.Stop()
+
+		run()
+		for range ticker.C {
+			run()
+		}
+	}()
+
+	log.Printf("⏱️  Agendador iniciado (intervalo de %s).\n", config.SchedulerInterval)
+}
+
+// run executa uma rodada de todas as tarefas
+func run() {
+	db, err := database.Connect()
+	if err != nil {
+		log.Println("Agendador: erro ao conectar ao banco:", err)
+		return
+	}
+	defer db.Close()
+
+	publishScheduledPosts(repository.NewPostsRepository(db))
+	purgeTrash(repository.NewTrashRepository(db))
+	purgeOrphanAttachments(repository.NewAttachmentsRepository(db))
+	purgeExpiredMutes(repository.NewMutesRepository(db))
+
+	if time.Since(lastBadgeAward) >= config.BadgeInterval {
+		awardBadges(repository.NewBadgesRepository(db))
+		lastBadgeAward = time.Now()
+	}
+}
+
+// publishScheduledPosts publica os posts cuja data agendada já chegou
+func publishScheduledPosts(repo *repository.PostsRepository) {
+	for {
+		ids, err := repo.PublishDue(publishBatchSize)
+		if err != nil {
+			log.Println("Agendador: erro ao publicar posts agendados:", err)
+			return
+		}
+
+		if len(ids) > 0 {
+			log.Printf("Agendador: %d post(s) publicado(s).\n", len(ids))
+		}
+
+		if len(ids) < publishBatchSize {
+			return
+		}
+	}
+}
+
+// purgeTrash remove definitivamente os itens que passaram do período de retenção da lixeira
+func purgeTrash(repo *repository.TrashRepository) {
+	before := time.Now().Add(-config.TrashRetention)
+
+	for {
+		ids, err := repo.PurgePosts(before, purgeBatchSize)
+		if err != nil {
+			log.Println("Agendador: erro ao remover posts da lixeira:", err)
+			return
+		}
+
+		if len(ids) > 0 {
+			log.Printf("Agendador: %d post(s) removido(s) definitivamente.\n", len(ids))
+		}
+
+		if len(ids) < purgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		purged, err := repo.PurgeComments(before, purgeBatchSize)
+		if err != nil {
+			log.Println("Agendador: erro ao remover comentários da lixeira:", err)
+			return
+		}
+
+		if purged > 0 {
+			log.Printf("Agendador: %d comentário(s) removido(s) definitivamente.\n", purged)
+		}
+
+		if purged < purgeBatchSize {
+			return
+		}
+	}
+}
+
+// purgeOrphanAttachments apaga do armazenamento os anexos de posts e comentários
+// removidos definitivamente
+func purgeOrphanAttachments(repo *repository.AttachmentsRepository) {
+	store, err := storage.New()
+	if err != nil {
+		log.Println("Agendador: erro ao acessar armazenamento:", err)
+		return
+	}
+
+	for {
+		orphans, err := repo.GetOrphans(orphanBatchSize)
+		if err != nil {
+			log.Println("Agendador: erro ao buscar anexos órfãos:", err)
+			return
+		}
+
+		removed := 0
+		for _, attachment := range orphans {
+			if err := deleteAttachmentFiles(store, attachment); err != nil {
+				// Um arquivo com problema não pode travar os demais: tenta de novo mais tarde
+				log.Printf("Agendador: erro ao remover arquivo do anexo %d: %v\n", attachment.ID, err)
+				if err := repo.DeferPurge(attachment.ID); err != nil {
+					log.Println("Agendador: erro ao adiar remoção do anexo:", err)
+					return
+				}
+				continue
+			}
+			if err := repo.Delete(attachment.ID); err != nil {
+				log.Println("Agendador: erro ao remover anexo:", err)
+				return
+			}
+			removed++
+		}
+
+		if removed > 0 {
+			log.Printf("Agendador: %d anexo(s) órfão(s) removido(s).\n", removed)
+		}
+
+		if len(orphans) < orphanBatchSize {
+			return
+		}
+	}
+}
+
+// deleteAttachmentFiles apaga do armazenamento o original e as variantes do anexo
+func deleteAttachmentFiles(store storage.Storage, attachment model.Attachment) error {
+	for _, key := range attachment.StorageKeys() {
+		if err := store.Delete(context.Background(), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeExpiredMutes apaga silêncios vencidos; as consultas já os ignoram, então
+// isto só mantém as tabelas enxutas
+func purgeExpiredMutes(repo *repository.MutesRepository) {
+	for {
+		purged, err := repo.PurgeExpired(mutesBatchSize)
+		if err != nil {
+			log.Println("Agendador: erro ao remover silêncios vencidos:", err)
+			return
+		}
+
+		if purged < mutesBatchSize {
+			return
+		}
+	}
+}
+
+// awardBadges concede as conquistas automáticas a quem atingiu suas regras
+func awardBadges(repo *repository.BadgesRepository) {
+	for {
+		awarded, pending, err := repo.Award(badgesBatchSize)
+		if err != nil {
+			log.Println("Agendador: erro ao conceder conquistas:", err)
+			return
+		}
+
+		if awarded > 0 {
+			log.Printf("Agendador: %d conquista(s) concedida(s).\n", awarded)
+		}
+
+		if !pending {
+			return
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Armazenamento de arquivos enviados pelos usuários
+package storage
+
+import (
+	"api/src/config"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Storage abstrai onde os arquivos ficam guardados (disco local ou serviço compatível com S3)
+type Storage interface {
+	// Put grava o conteúdo na chave informada
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Delete remove a chave; remover uma chave inexistente não é erro
+	Delete(ctx context.Context, key string) error
+	// URL retorna o endereço público da chave
+	URL(key string) string
+}
+
+// New cria o armazenamento configurado em STORAGE_DRIVER
+func New() (Storage, error) {
+	switch config.StorageDriver {
+	case "", "local":
+		return NewLocal(config.StorageLocalDir, baseURL()), nil
+	case "s3":
+		return NewS3(config.S3Endpoint, config.S3AccessKey, config.S3SecretKey, config.S3Bucket, config.S3Region, config.S3UseSSL, baseURL())
+	default:
+		return nil, fmt.Errorf("driver de armazenamento desconhecido: %s", config.StorageDriver)
+	}
+}
+
+// PublicURL retorna o endereço público de uma chave no armazenamento configurado
+func PublicURL(key string) string {
+	return baseURL() + "/" + strings.TrimLeft(key, "/")
+}
+
+// baseURL é o endereço base dos arquivos: STORAGE_PUBLIC_URL ou, no S3, o próprio bucket
+func baseURL() string {
+	if config.StoragePublicURL != "" {
+		return strings.TrimRight(config.StoragePublicURL, "/")
+	}
+
+	scheme := "http"
+	if config.S3UseSSL {
+		scheme = "https"
+	}
+	return scheme + "://" + config.S3Endpoint + "/" + config.S3Bucket
+}
+
+// NewKey gera uma chave aleatória, difícil de adivinhar, dentro do prefixo informado
+func NewKey(prefix, extension string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	name := hex.EncodeToString(random)
+	return path.Join(prefix, name[:2], name+extension), nil
+}
+
+// VariantKey deriva a chave de um tamanho gerado a partir da chave principal:
+// "a/b/abc.jpg" -> "a/b/abc_thumb.jpg"
+func VariantKey(key, name string) string {
+	extension := path.Ext(key)
+	return strings.TrimSuffix(key, extension) + "_" + name + extension
+}